@@ -0,0 +1,392 @@
+// Package editor is a small line editor for the REPL in the spirit of
+// linenoise: the terminal is put in raw mode while a line is read so arrow
+// keys, history and completion can be handled here instead of printing
+// escape codes.
+//
+// Keys: left/right, ctrl-b/ctrl-f move, home/end and ctrl-a/ctrl-e jump,
+// up/down and ctrl-p/ctrl-n walk the history, ctrl-r searches it, tab
+// completes, ctrl-k/ctrl-u/ctrl-w delete, ctrl-l clears the screen, ctrl-c
+// drops the line and ctrl-d on an empty line ends input.
+package editor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInterrupted is returned by ReadLine when ctrl-c is pressed
+var ErrInterrupted = errors.New("interrupted")
+
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlG     = 7
+	keyBackspace = 8
+	keyTab       = 9
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlR     = 18
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// keys that escape sequences are translated to, outside the rune range
+// typed characters can take
+const (
+	keyUp = unicode.MaxRune + 1 + iota
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyDeleteForward
+	keyUnknown
+)
+
+type Editor struct {
+	in     *os.File
+	out    io.Writer
+	reader *bufio.Reader
+
+	history     []string
+	historyFile string
+
+	// complete returns the candidates for the identifier before the cursor
+	complete func(prefix string) []string
+
+	// line being edited
+	prompt string
+	buf    []rune
+	pos    int
+}
+
+// New returns an editor reading key presses from in, which must be a
+// terminal. History is loaded from and saved to historyFile unless it is
+// empty.
+func New(in *os.File, out io.Writer, historyFile string) (*Editor, error) {
+	_, err := getState(in.Fd())
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a terminal: %w", in.Name(), err)
+	}
+
+	e := &Editor{in: in, out: out, reader: bufio.NewReader(in), historyFile: historyFile}
+	e.loadHistory()
+	return e, nil
+}
+
+// SetReader makes the editor read key presses through reader, which must
+// read from the editor's terminal. Sharing one reader with other code that
+// reads the terminal keeps either from buffering away the other's input.
+func (e *Editor) SetReader(reader *bufio.Reader) {
+	e.reader = reader
+}
+
+// SetCompleter sets the function used for tab completion
+func (e *Editor) SetCompleter(complete func(prefix string) []string) {
+	e.complete = complete
+}
+
+// ReadLine shows prompt and returns the line typed after it. Non-blank
+// lines are added to the history.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	state, err := makeRaw(e.in.Fd())
+	if err != nil {
+		return "", err
+	}
+	defer setState(e.in.Fd(), state)
+
+	e.prompt, e.buf, e.pos = prompt, nil, 0
+	e.refresh()
+
+	// position in the history while walking it, and the line that was being
+	// typed before walking started
+	index, scratch := len(e.history), ""
+
+	// a key that ended a search, handled as if it was just typed
+	var pending rune
+	replay := false
+	for {
+		key := pending
+		if replay {
+			replay = false
+		} else if key, err = e.readKey(); err != nil {
+			return "", err
+		}
+
+		switch key {
+		case keyEnter, '\n':
+			e.write("\r\n")
+			line := string(e.buf)
+			e.AddHistory(line)
+			return line, nil
+		case keyCtrlC:
+			e.write("^C\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(e.buf) == 0 {
+				e.write("\r\n")
+				return "", io.EOF
+			}
+			e.deleteAt(e.pos)
+		case keyDeleteForward:
+			e.deleteAt(e.pos)
+		case keyBackspace, keyDelete:
+			if e.pos > 0 {
+				e.pos--
+				e.deleteAt(e.pos)
+			}
+		case keyCtrlA, keyHome:
+			e.pos = 0
+		case keyCtrlE, keyEnd:
+			e.pos = len(e.buf)
+		case keyCtrlB, keyLeft:
+			e.pos = max(e.pos-1, 0)
+		case keyCtrlF, keyRight:
+			e.pos = min(e.pos+1, len(e.buf))
+		case keyCtrlK:
+			e.buf = e.buf[:e.pos]
+		case keyCtrlU:
+			e.buf = e.buf[e.pos:]
+			e.pos = 0
+		case keyCtrlW:
+			start := e.pos
+			for start > 0 && e.buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && e.buf[start-1] != ' ' {
+				start--
+			}
+			e.buf = append(e.buf[:start], e.buf[e.pos:]...)
+			e.pos = start
+		case keyCtrlL:
+			e.write("\x1b[H\x1b[2J")
+		case keyCtrlP, keyUp, keyCtrlN, keyDown:
+			if index == len(e.history) {
+				scratch = string(e.buf)
+			}
+			if key == keyCtrlP || key == keyUp {
+				index = max(index-1, 0)
+			} else {
+				index = min(index+1, len(e.history))
+			}
+			line := scratch
+			if index < len(e.history) {
+				line = e.history[index]
+			}
+			e.buf = []rune(line)
+			e.pos = len(e.buf)
+		case keyTab:
+			e.completeWord()
+		case keyCtrlR:
+			pending, replay, err = e.search()
+			if err != nil {
+				return "", err
+			}
+		default:
+			if key >= ' ' && key <= unicode.MaxRune {
+				e.insert([]rune{key})
+			}
+		}
+		e.refresh()
+	}
+}
+
+// readKey reads one key press, translating escape sequences for the
+// arrow, home, end and delete keys
+func (e *Editor) readKey() (rune, error) {
+	r, _, err := e.reader.ReadRune()
+	if err != nil || r != keyEscape {
+		return r, err
+	}
+
+	next, _, err := e.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if next != '[' && next != 'O' {
+		return keyUnknown, nil
+	}
+	code, _, err := e.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+
+	switch code {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	case 'C':
+		return keyRight, nil
+	case 'D':
+		return keyLeft, nil
+	case 'H':
+		return keyHome, nil
+	case 'F':
+		return keyEnd, nil
+	}
+	if code < '0' || code > '9' {
+		return keyUnknown, nil
+	}
+
+	// sequences like ESC [ 3 ~ or ESC [ 1 ; 5 C carry numbers
+	number := string(code)
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if r == '~' {
+			break
+		}
+		if r < '0' || r > '9' {
+			return keyUnknown, nil
+		}
+		number += string(r)
+	}
+	switch number {
+	case "1", "7":
+		return keyHome, nil
+	case "4", "8":
+		return keyEnd, nil
+	case "3":
+		return keyDeleteForward, nil
+	}
+	return keyUnknown, nil
+}
+
+func (e *Editor) insert(runes []rune) {
+	e.buf = append(e.buf[:e.pos], append(runes, e.buf[e.pos:]...)...)
+	e.pos += len(runes)
+}
+
+func (e *Editor) deleteAt(pos int) {
+	if pos < len(e.buf) {
+		e.buf = append(e.buf[:pos], e.buf[pos+1:]...)
+	}
+}
+
+// refresh redraws the prompt and line, scrolling sideways when the line is
+// wider than the terminal
+func (e *Editor) refresh() {
+	promptWidth := utf8.RuneCountInString(e.prompt)
+	room := max(width(e.in.Fd())-promptWidth-1, 1)
+
+	start := max(e.pos-room, 0)
+	end := min(start+room, len(e.buf))
+
+	var b strings.Builder
+	b.WriteString("\r" + e.prompt + string(e.buf[start:end]) + "\x1b[K\r")
+	if column := promptWidth + e.pos - start; column > 0 {
+		fmt.Fprintf(&b, "\x1b[%dC", column)
+	}
+	e.write(b.String())
+}
+
+func (e *Editor) write(s string) {
+	io.WriteString(e.out, s)
+}
+
+// completeWord completes the identifier before the cursor. With several
+// candidates it fills in what they have in common, or lists them if that
+// adds nothing.
+func (e *Editor) completeWord() {
+	if e.complete == nil {
+		return
+	}
+	start := e.pos
+	for start > 0 && isIdentifier(e.buf[start-1]) {
+		start--
+	}
+	prefix := string(e.buf[start:e.pos])
+	if prefix == "" {
+		return
+	}
+
+	candidates := e.complete(prefix)
+	if len(candidates) == 0 {
+		e.write("\a")
+		return
+	}
+	common := candidates[0]
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(common) > len(prefix) {
+		e.insert([]rune(common[len(prefix):]))
+		return
+	}
+	e.write("\r\n" + strings.Join(candidates, "  ") + "\r\n")
+}
+
+func isIdentifier(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// search is ctrl-r, it finds the newest history line containing what is
+// typed. Pressing ctrl-r again goes to older matches and ctrl-g gives up.
+// Any other key keeps the match and is returned, with true, for ReadLine to
+// handle as usual, so enter runs the match and arrows start editing it.
+func (e *Editor) search() (rune, bool, error) {
+	original, originalPos := e.buf, e.pos
+	var query []rune
+	index := len(e.history)
+	match := ""
+
+	find := func(from int) {
+		for i := min(from, len(e.history)-1); i >= 0; i-- {
+			if strings.Contains(e.history[i], string(query)) {
+				index, match = i, e.history[i]
+				return
+			}
+		}
+	}
+
+	for {
+		e.write(fmt.Sprintf("\r(reverse-i-search)`%s': %s\x1b[K", string(query), match))
+
+		key, err := e.readKey()
+		if err != nil {
+			return 0, false, err
+		}
+		switch {
+		case key == keyCtrlR:
+			find(index - 1)
+		case key == keyBackspace || key == keyDelete:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				index, match = len(e.history), ""
+				find(index)
+			}
+		case key == keyCtrlG || key == keyCtrlC:
+			e.buf, e.pos = original, originalPos
+			return 0, false, nil
+		case key >= ' ' && key <= unicode.MaxRune && key != keyDelete:
+			query = append(query, key)
+			find(index)
+		default:
+			if match != "" {
+				e.buf = []rune(match)
+				e.pos = len(e.buf)
+			}
+			return key, true, nil
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package editor
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// maxHistory is how many lines are kept, older ones are dropped on load
+const maxHistory = 1000
+
+// loadHistory reads the history file if there is one. Problems reading or
+// writing history never stop the editor from working.
+func (e *Editor) loadHistory() {
+	if e.historyFile == "" {
+		return
+	}
+	file, err := os.Open(e.historyFile)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		e.history = append(e.history, scanner.Text())
+	}
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history)-maxHistory:]
+		os.WriteFile(e.historyFile, []byte(strings.Join(e.history, "\n")+"\n"), 0600)
+	}
+}
+
+// AddHistory appends line to the history and the history file, skipping
+// blank lines and repeats of the previous line
+func (e *Editor) AddHistory(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+
+	if e.historyFile == "" {
+		return
+	}
+	file, err := os.OpenFile(e.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.WriteString(line + "\n")
+}
@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package editor
+
+import "errors"
+
+type termState struct{}
+
+var errUnsupported = errors.New("line editing is not supported on this platform")
+
+func getState(fd uintptr) (*termState, error) { return nil, errUnsupported }
+
+func setState(fd uintptr, state *termState) error { return errUnsupported }
+
+func makeRaw(fd uintptr) (*termState, error) { return nil, errUnsupported }
+
+func width(fd uintptr) int { return 80 }
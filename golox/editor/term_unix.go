@@ -0,0 +1,60 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package editor
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type termState struct {
+	termios syscall.Termios
+}
+
+func getState(fd uintptr) (*termState, error) {
+	var state termState
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&state.termios)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return &state, nil
+}
+
+func setState(fd uintptr, state *termState) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&state.termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// makeRaw turns off echo, line buffering and signal keys so every key
+// press reaches the editor, and returns the state to restore afterwards
+func makeRaw(fd uintptr) (*termState, error) {
+	old, err := getState(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *old
+	raw.termios.Iflag &^= syscall.BRKINT | syscall.ICRNL | syscall.INPCK | syscall.ISTRIP | syscall.IXON
+	raw.termios.Cflag |= syscall.CS8
+	raw.termios.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.IEXTEN | syscall.ISIG
+	raw.termios.Cc[syscall.VMIN] = 1
+	raw.termios.Cc[syscall.VTIME] = 0
+	err = setState(fd, &raw)
+	if err != nil {
+		return nil, err
+	}
+	return old, nil
+}
+
+// width is the number of columns in the terminal, 80 if it can't be found
+func width(fd uintptr) int {
+	var size struct{ rows, cols, x, y uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&size)))
+	if errno != 0 || size.cols == 0 {
+		return 80
+	}
+	return int(size.cols)
+}
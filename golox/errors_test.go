@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexleyoung/golox/lox"
+)
+
+var update = flag.Bool("update", false, "rewrite the .expected files in testdata/errors")
+
+// TestErrors checks that every malformed program in testdata/errors reports
+// exactly the diagnostics in its .expected file, the way `golox check` does
+func TestErrors(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "errors", "*.lox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no programs in testdata/errors")
+	}
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(strings.TrimSuffix(name, ".lox"), func(t *testing.T) {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var stderr bytes.Buffer
+			runtime := lox.NewRuntime()
+			runtime.SetStderr(&stderr)
+			if runtime.Check(name, string(source)) == nil {
+				t.Error("Check found no errors")
+			}
+
+			expectedFile := strings.TrimSuffix(file, ".lox") + ".expected"
+			if *update {
+				if err := os.WriteFile(expectedFile, stderr.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(expectedFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stderr.String(); got != string(expected) {
+				t.Errorf("diagnostics differ\n--- expected\n%s\n--- got\n%s", expected, got)
+			}
+		})
+	}
+}
@@ -2,100 +2,356 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/alexleyoung/golox/lox"
 )
 
-var reporter = NewErrorReporter()
+const usage = `Usage: golox [command] [flags] [script|-] [args...]
+
+Commands:
+  run      run a script, the default when a script is given
+  repl     start an interactive prompt, the default with no arguments
+  check    report errors without running (lex, parse and resolve only)
+  fmt      print a script in canonical format
+  ast      print the syntax tree of a script
+  tokens   print the tokens of a script
+
+Use - as the script to read it from stdin. Run 'golox <command> -h' for the
+flags of a command.
+`
+
+// exit codes from sysexits.h
+const (
+	exitUsage      = 64
+	exitDataErr    = 65
+	exitNoInput    = 66
+	exitSoftware   = 70
+	exitCantCreate = 73
+)
+
+type command struct {
+	name string
+	run  func(args []string) int
+}
+
+var commands = []command{
+	{"run", runCommand},
+	{"repl", replCommand},
+	{"check", checkCommand},
+	{"fmt", fmtCommand},
+	{"ast", astCommand},
+	{"tokens", tokensCommand},
+}
 
 func main() {
-	args := os.Args
-	switch len(args) {
-	case 1:
-		runPrompt()
-	case 2:
-		err := runFile(args[1])
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "-h", "-help", "--help", "help":
+			fmt.Print(usage)
+			return
 		}
-	default:
-		fmt.Println("Usage: golox [script]")
-		os.Exit(64)
+		for _, cmd := range commands {
+			if args[0] == cmd.name {
+				os.Exit(cmd.run(args[1:]))
+			}
+		}
+	}
+
+	// no command, behave like `golox run` or `golox repl`
+	if len(args) == 0 {
+		os.Exit(replCommand(args))
 	}
+	os.Exit(runCommand(args))
 }
 
-func runFile(path string) error {
-	f, err := os.ReadFile(path)
-	if err != nil {
-		return err
+// newFlagSet returns a FlagSet with the flags every command shares
+func newFlagSet(name, synopsis string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: golox %s %s\n", name, synopsis)
+		flags.PrintDefaults()
+	}
+	diagnostics := flags.String("diagnostics", lox.DiagnosticsText, "error output format: text or json")
+	return flags, diagnostics
+}
+
+func newRuntime(diagnostics string) (*lox.Runtime, bool) {
+	runtime := lox.NewRuntime()
+	if err := runtime.SetDiagnostics(diagnostics); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, false
 	}
+	return runtime, true
+}
 
-	err = run(string(f), NewInterpreter())
-	if err != nil {
-		if err.Error() == "lexical error" {
-			os.Exit(65)
-		}
-		if err.Error() == "parse error" {
-			os.Exit(65)
+func runCommand(args []string) int {
+	flags, diagnostics := newFlagSet("run", "[flags] [script|-] [args...]")
+	code := flags.String("e", "", "run `code` instead of a script")
+	flags.Parse(args)
+
+	runtime, ok := newRuntime(*diagnostics)
+	if !ok {
+		return exitUsage
+	}
+
+	name, source := "-e", *code
+	scriptArgs := flags.Args()
+	if *code == "" {
+		if flags.NArg() == 0 {
+			flags.Usage()
+			return exitUsage
 		}
-		if err.Error() == "runtime error" {
-			os.Exit(70)
+
+		var err error
+		name, source, err = readSource(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitNoInput
 		}
-		return err
+		scriptArgs = flags.Args()[1:]
 	}
 
-	return nil
+	runtime.SetArgs(scriptArgs)
+	_, err := runtime.EvalFile(name, source)
+	return exitCode(err)
 }
 
-func runPrompt() {
-	scanner := bufio.NewScanner(os.Stdin)
-	interpreter := NewInterpreter()
-	interpreter.repl = true
-	for true {
-		fmt.Print("\n> ")
-		in := scanner.Scan()
-		if !in {
-			if err := scanner.Err(); err != nil {
-				fmt.Println(err)
-			}
-			break
+func replCommand(args []string) int {
+	flags, diagnostics := newFlagSet("repl", "[flags]")
+	flags.Parse(args)
+
+	runtime, ok := newRuntime(*diagnostics)
+	if !ok {
+		return exitUsage
+	}
+	runtime.SetArgs(flags.Args())
+
+	// the prompt and the input native read lines through the same buffer
+	stdin := bufio.NewReader(os.Stdin)
+	runtime.SetStdin(stdin)
+	runPrompt(runtime, stdin)
+	return 0
+}
+
+func checkCommand(args []string) int {
+	flags, diagnostics := newFlagSet("check", "[flags] script|-...")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	runtime, ok := newRuntime(*diagnostics)
+	if !ok {
+		return exitUsage
+	}
+
+	// keep going so one run reports problems in every file
+	status := 0
+	for _, path := range flags.Args() {
+		name, source, err := readSource(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = max(status, exitNoInput)
+			continue
 		}
+		if err := runtime.Check(name, source); err != nil {
+			status = max(status, exitCode(err))
+		}
+	}
+	return status
+}
+
+func fmtCommand(args []string) int {
+	flags, diagnostics := newFlagSet("fmt", "[flags] script|-")
+	write := flags.Bool("w", false, "write the result back to the script instead of stdout")
+	flags.Parse(args)
+	if flags.NArg() != 1 || (*write && flags.Arg(0) == "-") {
+		flags.Usage()
+		return exitUsage
+	}
 
-		line := scanner.Text()
-		run(line, interpreter)
-		reporter.Reset()
+	runtime, ok := newRuntime(*diagnostics)
+	if !ok {
+		return exitUsage
 	}
+
+	name, source, err := readSource(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitNoInput
+	}
+
+	formatted, err := runtime.Format(name, source)
+	if err != nil {
+		return exitCode(err)
+	}
+
+	if !*write {
+		fmt.Print(formatted)
+		return 0
+	}
+	if formatted == source {
+		return 0
+	}
+	err = os.WriteFile(flags.Arg(0), []byte(formatted), 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCantCreate
+	}
+	return 0
 }
 
-func run(source string, interpreter *Interpreter) error {
-	lexer := NewLexer(source)
-	tokens, lexErrors := lexer.ScanTokens()
+func astCommand(args []string) int {
+	flags, diagnostics := newFlagSet("ast", "[flags] script|-")
+	format := flags.String("format", "sexpr", "output format: sexpr or json")
+	flags.Parse(args)
+	if flags.NArg() != 1 || (*format != "sexpr" && *format != "json") {
+		flags.Usage()
+		return exitUsage
+	}
+
+	runtime, ok := newRuntime(*diagnostics)
+	if !ok {
+		return exitUsage
+	}
+
+	name, source, err := readSource(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitNoInput
+	}
 
-	for _, err := range lexErrors {
-		reporter.Report(err)
+	statements, err := runtime.Parse(name, source)
+	if err != nil {
+		return exitCode(err)
 	}
 
-	if reporter.HadError() {
-		return fmt.Errorf("lexical error")
+	var out []byte
+	if *format == "json" {
+		out, err = (&lox.AstJSON{}).MarshalProgram(statements)
+	} else {
+		var s string
+		s, err = (&lox.AstPrinter{}).PrintProgram(statements)
+		out = []byte(s)
 	}
+	if err != nil {
+		return exitCode(err)
+	}
+	os.Stdout.Write(out)
+	return 0
+}
 
-	parser := NewParser(tokens)
-	statements, _ := parser.Parse()
+func tokensCommand(args []string) int {
+	flags, diagnostics := newFlagSet("tokens", "[flags] script|-")
+	format := flags.String("format", "text", "output format: text or json")
+	flags.Parse(args)
+	if flags.NArg() != 1 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		return exitUsage
+	}
 
-	if parser.HadError() {
-		return fmt.Errorf("parse error")
+	runtime, ok := newRuntime(*diagnostics)
+	if !ok {
+		return exitUsage
 	}
 
-	err := interpreter.Interpret(statements)
+	name, source, err := readSource(flags.Arg(0))
 	if err != nil {
-		reporter.Report(err)
-		if runtimeErr, ok := err.(RuntimeError); ok {
-			fmt.Println(runtimeErr.Error())
-			return fmt.Errorf("runtime error")
+		fmt.Fprintln(os.Stderr, err)
+		return exitNoInput
+	}
+
+	tokens, err := runtime.Tokens(name, source)
+	if *format == "json" {
+		printTokensJSON(tokens)
+	} else {
+		printTokens(tokens)
+	}
+	return exitCode(err)
+}
+
+// printTokens writes one token per line as position, type, lexeme, literal
+func printTokens(tokens []lox.Token) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, token := range tokens {
+		position := fmt.Sprintf("%d:%d-%d:%d", token.Line, token.Column, token.EndLine, token.EndColumn)
+		literal := ""
+		if token.Literal != nil {
+			literal = fmt.Sprintf("%#v", token.Literal)
 		}
-		return err
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", position, token.Type, strconv.Quote(token.Lexeme), literal)
+	}
+	w.Flush()
+}
+
+type jsonToken struct {
+	Type      string `json:"type"`
+	Lexeme    string `json:"lexeme"`
+	Literal   any    `json:"literal"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Offset    int    `json:"offset"`
+}
+
+func printTokensJSON(tokens []lox.Token) {
+	out := make([]jsonToken, len(tokens))
+	for i, token := range tokens {
+		out[i] = jsonToken{
+			Type:      token.Type.String(),
+			Lexeme:    token.Lexeme,
+			Literal:   token.Literal,
+			Line:      token.Line,
+			Column:    token.Column,
+			EndLine:   token.EndLine,
+			EndColumn: token.EndColumn,
+			Offset:    token.Offset,
+		}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(out)
+}
+
+// readSource reads a script, or stdin when path is "-"
+func readSource(path string) (string, string, error) {
+	if path == "-" {
+		source, err := io.ReadAll(os.Stdin)
+		return "<stdin>", string(source), err
+	}
+
+	source, err := os.ReadFile(path)
+	return path, string(source), err
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var lexErr lox.LexerError
+	var parseErr lox.ParserError
+	var resolveErr lox.ResolverError
+	var runtimeErr lox.RuntimeError
+	switch {
+	case errors.As(err, &lexErr), errors.As(err, &parseErr), errors.As(err, &resolveErr):
+		return exitDataErr
+	case errors.As(err, &runtimeErr):
+		return exitSoftware
 	}
 
-	return nil
+	fmt.Fprintln(os.Stderr, err)
+	return 1
 }
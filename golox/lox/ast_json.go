@@ -0,0 +1,240 @@
+package lox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// AstJSON converts the tree into nested maps ready for encoding/json. Every
+// node has a "type" naming its Go type, tokens become their lexeme.
+type AstJSON struct {
+	// result of the last statement visited, StmtVisitor can't return it
+	stmt map[string]any
+}
+
+// MarshalProgram encodes stmts as an indented JSON array
+func (j *AstJSON) MarshalProgram(stmts []Stmt) ([]byte, error) {
+	nodes, err := j.stmts(stmts)
+	if err != nil {
+		return nil, err
+	}
+
+	// operators like < and && read better unescaped
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(nodes)
+	return buf.Bytes(), err
+}
+
+func (j *AstJSON) VisitAssignmentExpr(expr *AssignmentExpr) (any, error) {
+	return j.node("AssignmentExpr", "name", expr.Name.Lexeme).exprs(j, "value", expr.Expr)
+}
+
+func (j *AstJSON) VisitConditionalExpr(expr *ConditionalExpr) (any, error) {
+	return j.node("ConditionalExpr").exprs(j, "condition", expr.Condition, "then", expr.Then, "otherwise", expr.Otherwise)
+}
+
+func (j *AstJSON) VisitLogicalExpr(expr *LogicalExpr) (any, error) {
+	return j.node("LogicalExpr", "op", expr.Op.Lexeme).exprs(j, "left", expr.Left, "right", expr.Right)
+}
+
+func (j *AstJSON) VisitBinaryExpr(expr *BinaryExpr) (any, error) {
+	return j.node("BinaryExpr", "op", expr.Op.Lexeme).exprs(j, "left", expr.Left, "right", expr.Right)
+}
+
+func (j *AstJSON) VisitGroupingExpr(expr *GroupingExpr) (any, error) {
+	return j.node("GroupingExpr").exprs(j, "expr", expr.Expr)
+}
+
+func (j *AstJSON) VisitLiteralExpr(expr *LiteralExpr) (any, error) {
+	return j.node("LiteralExpr", "value", expr.Value), nil
+}
+
+func (j *AstJSON) VisitUnaryExpr(expr *UnaryExpr) (any, error) {
+	return j.node("UnaryExpr", "op", expr.Op.Lexeme).exprs(j, "expr", expr.Expr)
+}
+
+func (j *AstJSON) VisitCallExpr(expr *CallExpr) (any, error) {
+	args := make([]any, len(expr.Args))
+	for i, arg := range expr.Args {
+		node, err := arg.Accept(j)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = node
+	}
+	return j.node("CallExpr", "args", args).exprs(j, "callee", expr.Callee)
+}
+
+func (j *AstJSON) VisitGetExpr(expr *GetExpr) (any, error) {
+	return j.node("GetExpr", "name", expr.Name.Lexeme).exprs(j, "object", expr.Object)
+}
+
+func (j *AstJSON) VisitSetExpr(expr *SetExpr) (any, error) {
+	return j.node("SetExpr", "name", expr.Name.Lexeme).exprs(j, "object", expr.Object, "value", expr.Value)
+}
+
+func (j *AstJSON) VisitSuperExpr(expr *SuperExpr) (any, error) {
+	return j.node("SuperExpr", "method", expr.Method.Lexeme), nil
+}
+
+func (j *AstJSON) VisitThisExpr(expr *ThisExpr) (any, error) {
+	return j.node("ThisExpr"), nil
+}
+
+func (j *AstJSON) VisitVariableExpr(expr *VariableExpr) (any, error) {
+	return j.node("VariableExpr", "name", expr.Name.Lexeme), nil
+}
+
+func (j *AstJSON) VisitClassStmt(stmt ClassStmt) error {
+	node := j.node("ClassStmt", "name", stmt.Name.Lexeme, "superclass", nil)
+	if stmt.Superclass != nil {
+		node["superclass"] = stmt.Superclass.Name.Lexeme
+	}
+
+	methods := make([]any, len(stmt.Methods))
+	for i, method := range stmt.Methods {
+		err := method.Accept(j)
+		if err != nil {
+			return err
+		}
+		methods[i] = j.stmt
+	}
+	node["methods"] = methods
+
+	j.stmt = node
+	return nil
+}
+
+func (j *AstJSON) VisitFunctionStmt(stmt FunctionStmt) error {
+	params := make([]string, len(stmt.Params))
+	for i, param := range stmt.Params {
+		params[i] = param.Lexeme
+	}
+	body, err := j.stmts(stmt.Body)
+	if err != nil {
+		return err
+	}
+	j.stmt = j.node("FunctionStmt", "name", stmt.Name.Lexeme, "params", params, "body", body)
+	return nil
+}
+
+func (j *AstJSON) VisitVariableStmt(stmt VariableStmt) error {
+	node, err := j.node("VariableStmt", "name", stmt.Name.Lexeme).exprs(j, "initializer", stmt.Initializer)
+	j.stmt = node
+	return err
+}
+
+func (j *AstJSON) VisitExpressionStmt(stmt ExpressionStmt) error {
+	node, err := j.node("ExpressionStmt").exprs(j, "expr", stmt.Expr)
+	j.stmt = node
+	return err
+}
+
+func (j *AstJSON) VisitPrintStmt(stmt PrintStmt) error {
+	node, err := j.node("PrintStmt").exprs(j, "expr", stmt.Expr)
+	j.stmt = node
+	return err
+}
+
+func (j *AstJSON) VisitIfStmt(stmt IfStmt) error {
+	node, err := j.node("IfStmt").exprs(j, "guard", stmt.Guard)
+	if err != nil {
+		return err
+	}
+	node["then"], err = j.stmtOrNil(stmt.ThenBranch)
+	if err != nil {
+		return err
+	}
+	node["else"], err = j.stmtOrNil(stmt.ElseBranch)
+	j.stmt = node
+	return err
+}
+
+func (j *AstJSON) VisitWhileStmt(stmt WhileStmt) error {
+	node, err := j.node("WhileStmt").exprs(j, "condition", stmt.Condition, "increment", stmt.Increment)
+	if err != nil {
+		return err
+	}
+	node["body"], err = j.stmtOrNil(stmt.Body)
+	j.stmt = node
+	return err
+}
+
+func (j *AstJSON) VisitBlockStmt(stmt BlockStmt) error {
+	statements, err := j.stmts(stmt.Statements)
+	j.stmt = j.node("BlockStmt", "statements", statements)
+	return err
+}
+
+func (j *AstJSON) VisitReturnStmt(stmt ReturnStmt) error {
+	node, err := j.node("ReturnStmt").exprs(j, "value", stmt.Value)
+	j.stmt = node
+	return err
+}
+
+func (j *AstJSON) VisitBreakStmt(stmt BreakStmt) error {
+	j.stmt = j.node("BreakStmt")
+	return nil
+}
+
+func (j *AstJSON) VisitContinueStmt(stmt ContinueStmt) error {
+	j.stmt = j.node("ContinueStmt")
+	return nil
+}
+
+func (j *AstJSON) stmts(stmts []Stmt) ([]any, error) {
+	nodes := make([]any, len(stmts))
+	for i, stmt := range stmts {
+		node, err := j.stmtOrNil(stmt)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+func (j *AstJSON) stmtOrNil(stmt Stmt) (any, error) {
+	if stmt == nil {
+		return nil, nil
+	}
+	err := stmt.Accept(j)
+	if err != nil {
+		return nil, err
+	}
+	return j.stmt, nil
+}
+
+type jsonNode map[string]any
+
+// node builds a node of the given type from alternating keys and values
+func (j *AstJSON) node(nodeType string, fields ...any) jsonNode {
+	node := jsonNode{"type": nodeType}
+	for i := 0; i+1 < len(fields); i += 2 {
+		node[fields[i].(string)] = fields[i+1]
+	}
+	return node
+}
+
+// exprs adds alternating keys and expressions to the node, nil expressions
+// are kept as null so optional children are still visible
+func (n jsonNode) exprs(j *AstJSON, fields ...any) (jsonNode, error) {
+	for i := 0; i+1 < len(fields); i += 2 {
+		key := fields[i].(string)
+		expr, _ := fields[i+1].(Expr)
+		if expr == nil {
+			n[key] = nil
+			continue
+		}
+		value, err := expr.Accept(j)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		n[key] = value
+	}
+	return n, nil
+}
@@ -0,0 +1,266 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AstPrinter prints the tree as S-expressions. Expressions are returned from
+// the Visitor methods and always fit on one line. Statements are written to
+// out since StmtVisitor methods only return an error, and any statements
+// nested inside them go on their own lines, indented one level deeper:
+//
+//	(fun add (a b)
+//	  (return (+ a b)))
+type AstPrinter struct {
+	out   strings.Builder
+	depth int
+}
+
+// PrintProgram prints each top level statement starting on its own line
+func (p *AstPrinter) PrintProgram(stmts []Stmt) (string, error) {
+	p.out.Reset()
+	p.depth = 0
+	for _, stmt := range stmts {
+		err := stmt.Accept(p)
+		if err != nil {
+			return "", err
+		}
+		p.out.WriteString("\n")
+	}
+	return p.out.String(), nil
+}
+
+func (p *AstPrinter) Print(expr Expr) (string, error) {
+	result, err := expr.Accept(p)
+	if err != nil {
+		return "", err
+	}
+	s, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected type returned from accept: %T", result)
+	}
+	return s, nil
+}
+
+func (p *AstPrinter) VisitAssignmentExpr(expr *AssignmentExpr) (any, error) {
+	return p.parenthesize("=", NewVariableExpr(expr.Name), expr.Expr)
+}
+
+func (p *AstPrinter) VisitConditionalExpr(expr *ConditionalExpr) (any, error) {
+	return p.parenthesize("?:", expr.Condition, expr.Then, expr.Otherwise)
+}
+
+func (p *AstPrinter) VisitLogicalExpr(expr *LogicalExpr) (any, error) {
+	return p.parenthesize(expr.Op.Lexeme, expr.Left, expr.Right)
+}
+
+func (p *AstPrinter) VisitBinaryExpr(expr *BinaryExpr) (any, error) {
+	return p.parenthesize(expr.Op.Lexeme, expr.Left, expr.Right)
+}
+
+func (p *AstPrinter) VisitGroupingExpr(expr *GroupingExpr) (any, error) {
+	return p.parenthesize("group", expr.Expr)
+}
+
+func (p *AstPrinter) VisitLiteralExpr(expr *LiteralExpr) (any, error) {
+	switch value := expr.Value.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		return fmt.Sprintf("%q", value), nil
+	}
+	return fmt.Sprintf("%v", expr.Value), nil
+}
+
+func (p *AstPrinter) VisitUnaryExpr(expr *UnaryExpr) (any, error) {
+	return p.parenthesize(expr.Op.Lexeme, expr.Expr)
+}
+
+func (p *AstPrinter) VisitCallExpr(expr *CallExpr) (any, error) {
+	return p.parenthesize("call", append([]Expr{expr.Callee}, expr.Args...)...)
+}
+
+func (p *AstPrinter) VisitGetExpr(expr *GetExpr) (any, error) {
+	return p.parenthesize("get "+expr.Name.Lexeme, expr.Object)
+}
+
+func (p *AstPrinter) VisitSetExpr(expr *SetExpr) (any, error) {
+	return p.parenthesize("set "+expr.Name.Lexeme, expr.Object, expr.Value)
+}
+
+func (p *AstPrinter) VisitSuperExpr(expr *SuperExpr) (any, error) {
+	return "(super " + expr.Method.Lexeme + ")", nil
+}
+
+func (p *AstPrinter) VisitThisExpr(expr *ThisExpr) (any, error) {
+	return "this", nil
+}
+
+func (p *AstPrinter) VisitVariableExpr(expr *VariableExpr) (any, error) {
+	return expr.Name.Lexeme, nil
+}
+
+func (p *AstPrinter) VisitClassStmt(stmt ClassStmt) error {
+	p.out.WriteString("(class " + stmt.Name.Lexeme)
+	if stmt.Superclass != nil {
+		p.out.WriteString(" (< " + stmt.Superclass.Name.Lexeme + ")")
+	}
+	methods := make([]Stmt, len(stmt.Methods))
+	for i, method := range stmt.Methods {
+		methods[i] = method
+	}
+	err := p.writeStmts(methods)
+	if err != nil {
+		return err
+	}
+	p.out.WriteString(")")
+	return nil
+}
+
+func (p *AstPrinter) VisitFunctionStmt(stmt FunctionStmt) error {
+	params := make([]string, len(stmt.Params))
+	for i, param := range stmt.Params {
+		params[i] = param.Lexeme
+	}
+	p.out.WriteString("(fun " + stmt.Name.Lexeme + " (" + strings.Join(params, " ") + ")")
+	err := p.writeStmts(stmt.Body)
+	if err != nil {
+		return err
+	}
+	p.out.WriteString(")")
+	return nil
+}
+
+func (p *AstPrinter) VisitVariableStmt(stmt VariableStmt) error {
+	if stmt.Initializer == nil {
+		p.out.WriteString("(var " + stmt.Name.Lexeme + ")")
+		return nil
+	}
+	return p.writeExprs("var "+stmt.Name.Lexeme, stmt.Initializer)
+}
+
+func (p *AstPrinter) VisitExpressionStmt(stmt ExpressionStmt) error {
+	return p.writeExprs(";", stmt.Expr)
+}
+
+func (p *AstPrinter) VisitPrintStmt(stmt PrintStmt) error {
+	return p.writeExprs("print", stmt.Expr)
+}
+
+func (p *AstPrinter) VisitIfStmt(stmt IfStmt) error {
+	guard, err := p.Print(stmt.Guard)
+	if err != nil {
+		return err
+	}
+	p.out.WriteString("(if " + guard)
+	branches := []Stmt{stmt.ThenBranch}
+	if stmt.ElseBranch != nil {
+		branches = append(branches, stmt.ElseBranch)
+	}
+	err = p.writeStmts(branches)
+	if err != nil {
+		return err
+	}
+	p.out.WriteString(")")
+	return nil
+}
+
+func (p *AstPrinter) VisitWhileStmt(stmt WhileStmt) error {
+	condition, err := p.Print(stmt.Condition)
+	if err != nil {
+		return err
+	}
+	p.out.WriteString("(while " + condition)
+	err = p.writeStmts([]Stmt{stmt.Body})
+	if err != nil {
+		return err
+	}
+	if stmt.Increment != nil {
+		increment, err := p.Print(stmt.Increment)
+		if err != nil {
+			return err
+		}
+		p.newline(p.depth + 1)
+		p.out.WriteString(increment)
+	}
+	p.out.WriteString(")")
+	return nil
+}
+
+func (p *AstPrinter) VisitBlockStmt(stmt BlockStmt) error {
+	p.out.WriteString("(block")
+	err := p.writeStmts(stmt.Statements)
+	if err != nil {
+		return err
+	}
+	p.out.WriteString(")")
+	return nil
+}
+
+func (p *AstPrinter) VisitReturnStmt(stmt ReturnStmt) error {
+	if stmt.Value == nil {
+		p.out.WriteString("(return)")
+		return nil
+	}
+	return p.writeExprs("return", stmt.Value)
+}
+
+func (p *AstPrinter) VisitBreakStmt(stmt BreakStmt) error {
+	p.out.WriteString("(break)")
+	return nil
+}
+
+func (p *AstPrinter) VisitContinueStmt(stmt ContinueStmt) error {
+	p.out.WriteString("(continue)")
+	return nil
+}
+
+// writeStmts writes each statement on its own line one level deeper
+func (p *AstPrinter) writeStmts(stmts []Stmt) error {
+	p.depth++
+	defer func() { p.depth-- }()
+
+	for _, stmt := range stmts {
+		p.newline(p.depth)
+		err := stmt.Accept(p)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *AstPrinter) newline(depth int) {
+	p.out.WriteString("\n")
+	p.out.WriteString(strings.Repeat("  ", depth))
+}
+
+func (p *AstPrinter) writeExprs(name string, exprs ...Expr) error {
+	s, err := p.parenthesize(name, exprs...)
+	if err != nil {
+		return err
+	}
+	p.out.WriteString(s)
+	return nil
+}
+
+func (p *AstPrinter) parenthesize(name string, exprs ...Expr) (string, error) {
+	var builder strings.Builder
+	builder.WriteString("(")
+	builder.WriteString(name)
+	for _, expr := range exprs {
+		builder.WriteString(" ")
+		s, err := expr.Accept(p)
+		if err != nil {
+			return "", err
+		}
+		str, ok := s.(string)
+		if !ok {
+			return "", fmt.Errorf("unexpected type returned from accept: %T", s)
+		}
+		builder.WriteString(str)
+	}
+	builder.WriteString(")")
+	return builder.String(), nil
+}
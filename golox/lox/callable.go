@@ -0,0 +1,26 @@
+package lox
+
+import "time"
+
+type Callable interface {
+	Value
+	Arity() int
+	Call(interpreter *Interpreter, args []Value) (Value, error)
+}
+
+type ClockNativeFn struct{}
+
+func (c ClockNativeFn) Arity() int { return 0 }
+
+func (c ClockNativeFn) Call(interpreter *Interpreter, args []Value) (Value, error) {
+	return Number(float64(time.Now().UnixMilli()) / 1000.0), nil
+}
+
+func (c ClockNativeFn) Kind() Kind     { return FunctionKind }
+func (c ClockNativeFn) String() string { return "<native fn>" }
+func (c ClockNativeFn) Truthy() bool   { return true }
+
+func (c ClockNativeFn) Equals(other Value) bool {
+	_, ok := other.(ClockNativeFn)
+	return ok
+}
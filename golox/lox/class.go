@@ -0,0 +1,85 @@
+package lox
+
+type LoxClass struct {
+	Name       string
+	superclass *LoxClass
+	methods    map[string]*LoxFunction
+}
+
+func NewLoxClass(name string, superclass *LoxClass, methods map[string]*LoxFunction) *LoxClass {
+	return &LoxClass{Name: name, superclass: superclass, methods: methods}
+}
+
+// findMethod walks up the superclass chain until the method is found
+func (c *LoxClass) findMethod(name string) (*LoxFunction, bool) {
+	if method, ok := c.methods[name]; ok {
+		return method, true
+	}
+	if c.superclass != nil {
+		return c.superclass.findMethod(name)
+	}
+	return nil, false
+}
+
+// classes are called like functions to construct instances
+func (c *LoxClass) Arity() int {
+	if initializer, ok := c.findMethod("init"); ok {
+		return initializer.Arity()
+	}
+	return 0
+}
+
+func (c *LoxClass) Call(interpreter *Interpreter, args []Value) (Value, error) {
+	instance := NewLoxInstance(c)
+	if initializer, ok := c.findMethod("init"); ok {
+		_, err := initializer.bind(instance).Call(interpreter, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return instance, nil
+}
+
+func (c *LoxClass) Kind() Kind     { return ClassKind }
+func (c *LoxClass) String() string { return c.Name }
+func (c *LoxClass) Truthy() bool   { return true }
+
+func (c *LoxClass) Equals(other Value) bool {
+	o, ok := other.(*LoxClass)
+	return ok && c == o
+}
+
+type LoxInstance struct {
+	class  *LoxClass
+	fields map[string]Value
+}
+
+func NewLoxInstance(class *LoxClass) *LoxInstance {
+	return &LoxInstance{class: class, fields: make(map[string]Value)}
+}
+
+// fields shadow methods of the same name
+func (i *LoxInstance) Get(name Token) (Value, error) {
+	if value, ok := i.fields[name.Lexeme]; ok {
+		return value, nil
+	}
+
+	if method, ok := i.class.findMethod(name.Lexeme); ok {
+		return method.bind(i), nil
+	}
+
+	return nil, NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'.")
+}
+
+func (i *LoxInstance) Set(name Token, value Value) {
+	i.fields[name.Lexeme] = value
+}
+
+func (i *LoxInstance) Kind() Kind     { return InstanceKind }
+func (i *LoxInstance) String() string { return i.class.Name + " instance" }
+func (i *LoxInstance) Truthy() bool   { return true }
+
+func (i *LoxInstance) Equals(other Value) bool {
+	o, ok := other.(*LoxInstance)
+	return ok && i == o
+}
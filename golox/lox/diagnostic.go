@@ -0,0 +1,271 @@
+package lox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	DiagnosticsText = "text"
+	DiagnosticsJSON = "json"
+)
+
+// Diagnostics collects the errors found while lexing, parsing, resolving and
+// running a program. One is shared by every phase and nothing is printed
+// when an error is added, the owner decides how and where to render them,
+// eg. with an ErrorReporter.
+type Diagnostics struct {
+	errors []error
+}
+
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{}
+}
+
+func (d *Diagnostics) Add(err error) {
+	d.errors = append(d.errors, err)
+}
+
+// Errors returns a copy of the collected errors in the order they were found
+func (d *Diagnostics) Errors() []error {
+	return slices.Clone(d.errors)
+}
+
+func (d *Diagnostics) HasErrors() bool {
+	return len(d.errors) > 0
+}
+
+// Clear forgets the collected errors, eg. once they have been rendered
+func (d *Diagnostics) Clear() {
+	d.errors = nil
+}
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiBlue  = "\x1b[34m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// Renderer prints errors the way compilers do, with the offending source
+// line and the span underlined:
+//
+//	error[E200]: Expect ';' after value.
+//	 --> script.lox:3:8
+//	  |
+//	3 | print x
+//	  |        ^
+//	  = hint: ...
+type Renderer struct {
+	Color bool
+
+	out    io.Writer
+	name   string
+	source string
+}
+
+func NewRenderer(out io.Writer, name, source string) *Renderer {
+	return &Renderer{out: out, name: name, source: source, Color: isTerminal(out)}
+}
+
+func (r *Renderer) Render(err LoxError) {
+	span := err.Span()
+
+	severity := "error"
+	if _, ok := err.(RuntimeError); ok {
+		severity = "runtime error"
+	}
+	fmt.Fprintf(r.out, "%s: %s\n", r.paint(ansiBold+ansiRed, fmt.Sprintf("%s[%s]", severity, err.Code())), r.paint(ansiBold, message(err)))
+
+	line, ok := r.line(span.Line)
+	if !ok {
+		fmt.Fprintf(r.out, "%s %s:%d:%d\n", r.paint(ansiBold+ansiBlue, "-->"), r.name, span.Line, span.Column)
+		for _, note := range err.Notes() {
+			fmt.Fprintf(r.out, "  = %s %s\n", r.paint(ansiBold+ansiCyan, "hint:"), note)
+		}
+		r.renderTrace(err)
+		return
+	}
+
+	number := fmt.Sprintf("%d", span.Line)
+	gutter := strings.Repeat(" ", len(number))
+	fmt.Fprintf(r.out, "%s%s %s:%d:%d\n", gutter, r.paint(ansiBold+ansiBlue, "-->"), r.name, span.Line, span.Column)
+	fmt.Fprintf(r.out, "%s %s\n", gutter, r.paint(ansiBold+ansiBlue, "|"))
+	fmt.Fprintf(r.out, "%s %s %s\n", r.paint(ansiBold+ansiBlue, number), r.paint(ansiBold+ansiBlue, "|"), line)
+	fmt.Fprintf(r.out, "%s %s %s\n", gutter, r.paint(ansiBold+ansiBlue, "|"), r.underline(line, span))
+	for _, note := range err.Notes() {
+		fmt.Fprintf(r.out, "%s %s %s %s\n", gutter, r.paint(ansiBold+ansiBlue, "="), r.paint(ansiBold+ansiCyan, "hint:"), note)
+	}
+	r.renderTrace(err)
+}
+
+func (r *Renderer) renderTrace(err LoxError) {
+	runtimeErr, ok := err.(RuntimeError)
+	if !ok || len(runtimeErr.Trace) == 0 {
+		return
+	}
+	for _, line := range strings.Split(runtimeErr.StackTrace(), "\n") {
+		fmt.Fprintf(r.out, "    %s\n", line)
+	}
+}
+
+// underline puts carets under the span, only up to the end of the first line
+// for spans that cover several
+func (r *Renderer) underline(line string, span Span) string {
+	runes := []rune(line)
+	start := min(max(span.Column-1, 0), len(runes))
+
+	width := 1
+	if span.EndLine == span.Line && span.EndColumn > span.Column {
+		width = span.EndColumn - span.Column
+	} else if span.EndLine > span.Line {
+		width = max(len(runes)-start, 1)
+	}
+
+	// keep tabs so the carets line up with what the terminal shows
+	var padding strings.Builder
+	for _, c := range runes[:start] {
+		if c == '\t' {
+			padding.WriteRune('\t')
+		} else {
+			padding.WriteRune(' ')
+		}
+	}
+	return padding.String() + r.paint(ansiBold+ansiRed, strings.Repeat("^", width))
+}
+
+// line returns the 1-based line of source without its newline
+func (r *Renderer) line(n int) (string, bool) {
+	lines := strings.Split(r.source, "\n")
+	if n < 1 || n > len(lines) {
+		return "", false
+	}
+	return strings.TrimRight(lines[n-1], "\r"), true
+}
+
+func (r *Renderer) paint(style, text string) string {
+	if !r.Color {
+		return text
+	}
+	return style + text + ansiReset
+}
+
+// message is the error text without the "[line N] Error:" prefix, the
+// position is already shown by the excerpt
+func message(err LoxError) string {
+	switch err := err.(type) {
+	case LexerError:
+		return err.Message
+	case ParserError:
+		if err.Token.Type == EOF {
+			return err.Message + " (at end)"
+		}
+		return err.Message
+	case ResolverError:
+		return err.Message
+	case RuntimeError:
+		return err.Message
+	}
+	return err.Error()
+}
+
+// isTerminal reports whether w is a character device, so ANSI codes won't
+// end up in files or pipes
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+type jsonSpan struct {
+	Line      int `json:"line"`
+	Column    int `json:"column"`
+	EndLine   int `json:"endLine"`
+	EndColumn int `json:"endColumn"`
+	Offset    int `json:"offset"`
+	Length    int `json:"length"`
+}
+
+type jsonFrame struct {
+	Function string `json:"function"`
+	Line     int    `json:"line"`
+}
+
+type jsonDiagnostic struct {
+	Kind    string      `json:"kind"`
+	Code    string      `json:"code,omitempty"`
+	Message string      `json:"message"`
+	File    string      `json:"file,omitempty"`
+	Line    int         `json:"line,omitempty"`
+	Column  int         `json:"column,omitempty"`
+	Span    *jsonSpan   `json:"span,omitempty"`
+	Notes   []string    `json:"notes,omitempty"`
+	Trace   []jsonFrame `json:"trace,omitempty"`
+}
+
+// diagnosticJSON encodes err as a single line JSON object for tools
+func diagnosticJSON(err error) []byte {
+	diagnostic := jsonDiagnostic{Kind: "error", Message: err.Error()}
+
+	if loxErr, ok := err.(LoxError); ok {
+		span := loxErr.Span()
+		if span.Source != nil {
+			diagnostic.File = span.Source.Name
+		}
+		diagnostic.Kind = errorKind(loxErr)
+		diagnostic.Code = loxErr.Code()
+		diagnostic.Message = message(loxErr)
+		diagnostic.Line = span.Line
+		diagnostic.Column = span.Column
+		diagnostic.Span = &jsonSpan{
+			Line:      span.Line,
+			Column:    span.Column,
+			EndLine:   span.EndLine,
+			EndColumn: span.EndColumn,
+			Offset:    span.Offset,
+			Length:    span.Length,
+		}
+		diagnostic.Notes = loxErr.Notes()
+	}
+
+	if runtimeErr, ok := err.(RuntimeError); ok && len(runtimeErr.Trace) > 0 {
+		for _, frame := range runtimeErr.frames() {
+			diagnostic.Trace = append(diagnostic.Trace, jsonFrame{Function: frame.function, Line: frame.line})
+		}
+	}
+
+	// keep "<script>" readable instead of escaping it for HTML
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(diagnostic)
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
+
+func errorKind(err LoxError) string {
+	switch err.(type) {
+	case LexerError:
+		return "lexer"
+	case ParserError:
+		return "parser"
+	case ResolverError:
+		return "resolver"
+	case RuntimeError:
+		return "runtime"
+	case EnvironmentError:
+		return "environment"
+	}
+	return "error"
+}
@@ -0,0 +1,63 @@
+package lox
+
+type Environment struct {
+	enclosing *Environment
+	values    map[string]Value
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{enclosing: nil, values: make(map[string]Value)}
+}
+
+func NewNestedEnvironment(enclosing *Environment) *Environment {
+	return &Environment{enclosing: enclosing, values: make(map[string]Value)}
+}
+
+func (e *Environment) define(name string, value Value) {
+	// not checking if "name" in `values` allows var redefinition
+	// could err if we want to disallow and force assignment instead
+	// doing so is not so friendly to REPLs though
+	e.values[name] = value
+}
+
+func (e *Environment) get(name Token) (Value, error) {
+	val, ok := e.values[name.Lexeme]
+	if !ok {
+		if e.enclosing != nil {
+			return e.enclosing.get(name)
+		}
+		return nil, NewEnvironmentError(name, "")
+	}
+	return val, nil
+}
+
+func (e *Environment) assign(name Token, value Value) error {
+	_, ok := e.values[name.Lexeme]
+	if !ok {
+		if e.enclosing != nil {
+			return e.enclosing.assign(name, value)
+		}
+		return NewEnvironmentError(name, "")
+	}
+
+	e.values[name.Lexeme] = value
+	return nil
+}
+
+// getAt and assignAt skip the scope walk for variables the Resolver has
+// already located distance scopes up the chain
+func (e *Environment) getAt(distance int, name string) Value {
+	return e.ancestor(distance).values[name]
+}
+
+func (e *Environment) assignAt(distance int, name Token, value Value) {
+	e.ancestor(distance).values[name.Lexeme] = value
+}
+
+func (e *Environment) ancestor(distance int) *Environment {
+	env := e
+	for range distance {
+		env = env.enclosing
+	}
+	return env
+}
@@ -0,0 +1,281 @@
+package lox
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// LoxError is implemented by every error that points at a place in the
+// source. Code identifies the kind of error and Notes are extra hints shown
+// under the source excerpt by the Renderer.
+type LoxError interface {
+	error
+	Line() int
+	Span() Span
+	Code() string
+	Notes() []string
+}
+
+const (
+	CodeLexer       = "E100"
+	CodeParser      = "E200"
+	CodeResolver    = "E300"
+	CodeRuntime     = "E400"
+	CodeEnvironment = "E410"
+)
+
+type LexerError struct {
+	span    Span
+	Message string
+	notes   []string
+}
+
+func (e LexerError) Error() string {
+	return fmt.Sprintf("[line %s] Error: %s", e.span, e.Message)
+}
+
+func (e LexerError) Line() int {
+	return e.span.Line
+}
+
+func (e LexerError) Span() Span {
+	return e.span
+}
+
+func (e LexerError) Code() string { return CodeLexer }
+
+func (e LexerError) Notes() []string { return e.notes }
+
+type ParserError struct {
+	Token   Token
+	Message string
+	notes   []string
+}
+
+func (e ParserError) Error() string {
+	where := " at 'token'"
+	if e.Token.Type == EOF {
+		where = " at end"
+	} else {
+		where = fmt.Sprintf(" at '%s'", e.Token.Lexeme)
+	}
+	return fmt.Sprintf("[line %s] Error%s: %s", e.Token.Span(), where, e.Message)
+}
+
+func (e ParserError) Line() int {
+	return e.Token.Line
+}
+
+func (e ParserError) Span() Span {
+	return e.Token.Span()
+}
+
+func (e ParserError) Code() string { return CodeParser }
+
+func (e ParserError) Notes() []string { return e.notes }
+
+type ResolverError struct {
+	Token   Token
+	Message string
+	notes   []string
+}
+
+func (e ResolverError) Error() string {
+	return fmt.Sprintf("[line %s] Error at '%s': %s", e.Token.Span(), e.Token.Lexeme, e.Message)
+}
+
+func (e ResolverError) Line() int {
+	return e.Token.Line
+}
+
+func (e ResolverError) Span() Span {
+	return e.Token.Span()
+}
+
+func (e ResolverError) Code() string { return CodeResolver }
+
+func (e ResolverError) Notes() []string { return e.notes }
+
+type RuntimeError struct {
+	Token   Token
+	Message string
+	notes   []string
+
+	// calls active when the error happened, outermost first, nil for errors
+	// in top level code
+	Trace []Frame
+}
+
+func (e RuntimeError) Error() string {
+	return fmt.Sprintf("[line %s] RuntimeError: %s", e.Token.Span(), e.Message)
+}
+
+func (e RuntimeError) Line() int {
+	return e.Token.Line
+}
+
+func (e RuntimeError) Span() Span {
+	return e.Token.Span()
+}
+
+func (e RuntimeError) Code() string { return CodeRuntime }
+
+// StackTrace lists the Lox call stack innermost first, one call per line:
+//
+//	at inner (line 3)
+//	at outer (line 7)
+//	at <script> (line 10)
+//
+// Very deep stacks, usually runaway recursion, have their middle elided.
+func (e RuntimeError) StackTrace() string {
+	frames := e.frames()
+	lines := make([]string, len(frames))
+	for i, frame := range frames {
+		lines[i] = fmt.Sprintf("at %s (line %d)", frame.function, frame.line)
+	}
+
+	const keep = 10
+	if len(lines) > 2*keep {
+		elided := fmt.Sprintf("... %d more calls ...", len(lines)-2*keep)
+		lines = slices.Concat(lines[:keep], []string{elided}, lines[len(lines)-keep:])
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e RuntimeError) Notes() []string { return e.notes }
+
+// traceFrame is a call in a stack trace and the line it had reached
+type traceFrame struct {
+	function string
+	line     int
+}
+
+// frames pairs each call in Trace, innermost first and ending with
+// <script>, with the line it was executing. Each frame's Call is where the
+// caller was, so lines shift by one frame.
+func (e RuntimeError) frames() []traceFrame {
+	frames := make([]traceFrame, 0, len(e.Trace)+1)
+	line := e.Token.Line
+	for i := len(e.Trace) - 1; i >= 0; i-- {
+		frames = append(frames, traceFrame{function: e.Trace[i].Function, line: line})
+		line = e.Trace[i].Call.Line
+	}
+	return append(frames, traceFrame{function: "<script>", line: line})
+}
+
+type EnvironmentError struct {
+	Name    Token
+	Message string
+}
+
+func (e EnvironmentError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("Undefined variable '%s'.", e.Name)
+	}
+	return fmt.Sprintf("Undefined variable '%s'. %s", e.Name, e.Message)
+}
+
+func (e EnvironmentError) Line() int {
+	return e.Name.Line
+}
+
+func (e EnvironmentError) Span() Span {
+	return e.Name.Span()
+}
+
+func (e EnvironmentError) Code() string { return CodeEnvironment }
+
+func (e EnvironmentError) Notes() []string { return nil }
+
+func NewLexerError(span Span, message string, notes ...string) LexerError {
+	return LexerError{span: span, Message: message, notes: notes}
+}
+
+func NewParserError(token Token, message string, notes ...string) ParserError {
+	return ParserError{Token: token, Message: message, notes: notes}
+}
+
+func NewResolverError(token Token, message string, notes ...string) ResolverError {
+	return ResolverError{Token: token, Message: message, notes: notes}
+}
+
+func NewRuntimeError(token Token, message string, notes ...string) RuntimeError {
+	return RuntimeError{Token: token, Message: message, notes: notes}
+}
+
+func NewEnvironmentError(name Token, message string) EnvironmentError {
+	return EnvironmentError{Name: name, Message: message}
+}
+
+// ReturnSignal is not a real error. It carries a return value up through
+// nested blocks and loops to the enclosing LoxFunction.Call.
+type ReturnSignal struct {
+	Value Value
+}
+
+func (r ReturnSignal) Error() string {
+	return "return outside of function call"
+}
+
+// BreakSignal and ContinueSignal unwind the body of the innermost loop.
+type BreakSignal struct{}
+
+func (b BreakSignal) Error() string {
+	return "break outside of loop"
+}
+
+type ContinueSignal struct{}
+
+func (c ContinueSignal) Error() string {
+	return "continue outside of loop"
+}
+
+// ErrorReporter renders errors to out, as text or JSON. It doesn't collect
+// them, that is what Diagnostics is for.
+type ErrorReporter struct {
+	out    io.Writer
+	color  bool
+	format string
+}
+
+func NewErrorReporter(out io.Writer) *ErrorReporter {
+	return &ErrorReporter{out: out, color: isTerminal(out), format: DiagnosticsText}
+}
+
+// SetFormat picks between human readable (DiagnosticsText) and one JSON
+// object per line (DiagnosticsJSON) output
+func (r *ErrorReporter) SetFormat(format string) error {
+	if format != DiagnosticsText && format != DiagnosticsJSON {
+		return fmt.Errorf("unknown diagnostics format %q", format)
+	}
+	r.format = format
+	return nil
+}
+
+// SetOutput changes where errors are written and whether they are colored
+func (r *ErrorReporter) SetOutput(out io.Writer) {
+	r.out, r.color = out, isTerminal(out)
+}
+
+func (r *ErrorReporter) Report(err error) {
+	if r.format == DiagnosticsJSON {
+		r.out.Write(append(diagnosticJSON(err), '\n'))
+		return
+	}
+
+	// errors are rendered against the source they were found in, which is
+	// not necessarily the one that just ran
+	if loxErr, ok := err.(LoxError); ok && loxErr.Span().Source != nil {
+		source := loxErr.Span().Source
+		renderer := NewRenderer(r.out, source.Name, source.Text)
+		renderer.Color = r.color
+		renderer.Render(loxErr)
+	} else {
+		fmt.Fprintln(r.out, err.Error())
+		if runtimeErr, ok := err.(RuntimeError); ok && len(runtimeErr.Trace) > 0 {
+			fmt.Fprintln(r.out, runtimeErr.StackTrace())
+		}
+	}
+}
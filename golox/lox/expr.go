@@ -0,0 +1,172 @@
+package lox
+
+type Expr interface {
+	Accept(v Visitor) (any, error)
+}
+
+type AssignmentExpr struct {
+	Name Token
+	Expr Expr
+}
+
+type ConditionalExpr struct {
+	Condition       Expr
+	Then, Otherwise Expr
+}
+
+type LogicalExpr struct {
+	Left, Right Expr
+	Op          Token
+}
+
+type BinaryExpr struct {
+	Left, Right Expr
+	Op          Token
+}
+
+type GroupingExpr struct {
+	Expr Expr
+}
+
+type LiteralExpr struct {
+	Value any
+}
+
+type UnaryExpr struct {
+	Expr Expr
+	Op   Token
+}
+
+type CallExpr struct {
+	Callee Expr
+	Paren  Token
+	Args   []Expr
+}
+
+type GetExpr struct {
+	Object Expr
+	Name   Token
+}
+
+type SetExpr struct {
+	Object Expr
+	Name   Token
+	Value  Expr
+}
+
+type SuperExpr struct {
+	Keyword Token
+	Method  Token
+}
+
+type ThisExpr struct {
+	Keyword Token
+}
+
+type VariableExpr struct {
+	Name Token
+}
+
+func NewAssignmentExpr(name Token, expr Expr) *AssignmentExpr {
+	return &AssignmentExpr{Name: name, Expr: expr}
+}
+
+func NewConditionalExpr(condition, then, otherwise Expr) *ConditionalExpr {
+	return &ConditionalExpr{Condition: condition, Then: then, Otherwise: otherwise}
+}
+
+func NewLogicalExpr(op Token, left, right Expr) *LogicalExpr {
+	return &LogicalExpr{Op: op, Left: left, Right: right}
+}
+
+func NewBinaryExpr(op Token, left, right Expr) *BinaryExpr {
+	return &BinaryExpr{Op: op, Left: left, Right: right}
+}
+
+func NewGroupingExpr(expr Expr) *GroupingExpr {
+	return &GroupingExpr{Expr: expr}
+}
+
+func NewLiteralExpr(value any) *LiteralExpr {
+	return &LiteralExpr{Value: value}
+}
+
+func NewUnaryExpr(op Token, expr Expr) *UnaryExpr {
+	return &UnaryExpr{Op: op, Expr: expr}
+}
+
+func NewCallExpr(callee Expr, paren Token, args []Expr) *CallExpr {
+	return &CallExpr{Callee: callee, Paren: paren, Args: args}
+}
+
+func NewGetExpr(object Expr, name Token) *GetExpr {
+	return &GetExpr{Object: object, Name: name}
+}
+
+func NewSetExpr(object Expr, name Token, value Expr) *SetExpr {
+	return &SetExpr{Object: object, Name: name, Value: value}
+}
+
+func NewSuperExpr(keyword Token, method Token) *SuperExpr {
+	return &SuperExpr{Keyword: keyword, Method: method}
+}
+
+func NewThisExpr(keyword Token) *ThisExpr {
+	return &ThisExpr{Keyword: keyword}
+}
+
+func NewVariableExpr(name Token) *VariableExpr {
+	return &VariableExpr{Name: name}
+}
+
+func (e *AssignmentExpr) Accept(v Visitor) (any, error) {
+	return v.VisitAssignmentExpr(e)
+}
+
+func (e *ConditionalExpr) Accept(v Visitor) (any, error) {
+	return v.VisitConditionalExpr(e)
+}
+
+func (e *LogicalExpr) Accept(v Visitor) (any, error) {
+	return v.VisitLogicalExpr(e)
+}
+
+func (e *BinaryExpr) Accept(v Visitor) (any, error) {
+	return v.VisitBinaryExpr(e)
+}
+
+func (e *GroupingExpr) Accept(v Visitor) (any, error) {
+	return v.VisitGroupingExpr(e)
+}
+
+func (e *LiteralExpr) Accept(v Visitor) (any, error) {
+	return v.VisitLiteralExpr(e)
+}
+
+func (e *UnaryExpr) Accept(v Visitor) (any, error) {
+	return v.VisitUnaryExpr(e)
+}
+
+func (e *CallExpr) Accept(v Visitor) (any, error) {
+	return v.VisitCallExpr(e)
+}
+
+func (e *GetExpr) Accept(v Visitor) (any, error) {
+	return v.VisitGetExpr(e)
+}
+
+func (e *SetExpr) Accept(v Visitor) (any, error) {
+	return v.VisitSetExpr(e)
+}
+
+func (e *SuperExpr) Accept(v Visitor) (any, error) {
+	return v.VisitSuperExpr(e)
+}
+
+func (e *ThisExpr) Accept(v Visitor) (any, error) {
+	return v.VisitThisExpr(e)
+}
+
+func (e *VariableExpr) Accept(v Visitor) (any, error) {
+	return v.VisitVariableExpr(e)
+}
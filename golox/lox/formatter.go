@@ -0,0 +1,661 @@
+package lox
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxWidth is the line length past which call arguments are wrapped
+const maxWidth = 80
+
+// Formatter prints a parsed program back as Lox source in the one canonical
+// style: two space indents, spaces around binary operators, opening braces
+// on the same line and at most one blank line between statements. There are
+// no options.
+//
+// The tree doesn't keep punctuation or comments, so the Formatter walks the
+// tokens the program was parsed from alongside it. Every token written
+// advances the cursor and picks up the comments the lexer attached to it.
+type Formatter struct {
+	tokens  []Token
+	current int
+	lines   []string
+	indent  int
+
+	// comments picked up while rendering the line being built
+	comments []Comment
+
+	// set while re-rendering a line that was too long, the first call
+	// visited puts its arguments on their own lines
+	wrap bool
+}
+
+func NewFormatter(tokens []Token) *Formatter {
+	// comments are cleared once written, so work on a copy
+	return &Formatter{tokens: append([]Token(nil), tokens...)}
+}
+
+// Format returns the formatted source of stmts, which must have been parsed
+// from the Formatter's tokens
+func (f *Formatter) Format(stmts []Stmt) (string, error) {
+	err := f.stmts(stmts)
+	if err != nil {
+		return "", err
+	}
+	// comments after the last statement are attached to EOF
+	f.leading()
+	if len(f.lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(f.lines, "\n") + "\n", nil
+}
+
+func (f *Formatter) VisitAssignmentExpr(expr *AssignmentExpr) (any, error) {
+	f.take(IDENTIFIER)
+	f.take(EQUAL)
+	value, err := f.expr(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Name.Lexeme + " = " + value, nil
+}
+
+func (f *Formatter) VisitConditionalExpr(expr *ConditionalExpr) (any, error) {
+	condition, err := f.expr(expr.Condition)
+	if err != nil {
+		return nil, err
+	}
+	f.take(QUESTION)
+	then, err := f.expr(expr.Then)
+	if err != nil {
+		return nil, err
+	}
+	f.take(COLON)
+	otherwise, err := f.expr(expr.Otherwise)
+	if err != nil {
+		return nil, err
+	}
+	return condition + " ? " + then + " : " + otherwise, nil
+}
+
+func (f *Formatter) VisitLogicalExpr(expr *LogicalExpr) (any, error) {
+	return f.binary(expr.Left, expr.Op, expr.Right)
+}
+
+func (f *Formatter) VisitBinaryExpr(expr *BinaryExpr) (any, error) {
+	return f.binary(expr.Left, expr.Op, expr.Right)
+}
+
+func (f *Formatter) VisitGroupingExpr(expr *GroupingExpr) (any, error) {
+	f.take(LEFT_PAREN)
+	inner, err := f.expr(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+	f.take(RIGHT_PAREN)
+	return "(" + inner + ")", nil
+}
+
+func (f *Formatter) VisitLiteralExpr(expr *LiteralExpr) (any, error) {
+	// the lexeme keeps numbers and strings exactly as they were written
+	return f.take(NUMBER, STRING, TRUE, FALSE, NIL).Lexeme, nil
+}
+
+func (f *Formatter) VisitUnaryExpr(expr *UnaryExpr) (any, error) {
+	f.take(expr.Op.Type)
+	operand, err := f.expr(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Op.Lexeme + operand, nil
+}
+
+func (f *Formatter) VisitCallExpr(expr *CallExpr) (any, error) {
+	wrap := f.wrap && len(expr.Args) > 0
+	if wrap {
+		f.wrap = false
+	}
+
+	callee, err := f.expr(expr.Callee)
+	if err != nil {
+		return nil, err
+	}
+	f.take(LEFT_PAREN)
+	args := make([]string, len(expr.Args))
+	for i, arg := range expr.Args {
+		if i > 0 {
+			f.take(COMMA)
+		}
+		args[i], err = f.expr(arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	f.take(RIGHT_PAREN)
+
+	if !wrap {
+		return callee + "(" + strings.Join(args, ", ") + ")", nil
+	}
+	outer := strings.Repeat("  ", f.indent)
+	inner := outer + "  "
+	return callee + "(\n" + inner + strings.Join(args, ",\n"+inner) + "\n" + outer + ")", nil
+}
+
+func (f *Formatter) VisitGetExpr(expr *GetExpr) (any, error) {
+	object, err := f.expr(expr.Object)
+	if err != nil {
+		return nil, err
+	}
+	f.take(DOT)
+	f.take(IDENTIFIER)
+	return object + "." + expr.Name.Lexeme, nil
+}
+
+func (f *Formatter) VisitSetExpr(expr *SetExpr) (any, error) {
+	object, err := f.expr(expr.Object)
+	if err != nil {
+		return nil, err
+	}
+	f.take(DOT)
+	f.take(IDENTIFIER)
+	f.take(EQUAL)
+	value, err := f.expr(expr.Value)
+	if err != nil {
+		return nil, err
+	}
+	return object + "." + expr.Name.Lexeme + " = " + value, nil
+}
+
+func (f *Formatter) VisitSuperExpr(expr *SuperExpr) (any, error) {
+	f.take(SUPER)
+	f.take(DOT)
+	f.take(IDENTIFIER)
+	return "super." + expr.Method.Lexeme, nil
+}
+
+func (f *Formatter) VisitThisExpr(expr *ThisExpr) (any, error) {
+	f.take(THIS)
+	return "this", nil
+}
+
+func (f *Formatter) VisitVariableExpr(expr *VariableExpr) (any, error) {
+	f.take(IDENTIFIER)
+	return expr.Name.Lexeme, nil
+}
+
+func (f *Formatter) VisitClassStmt(stmt ClassStmt) error {
+	f.take(CLASS)
+	f.take(IDENTIFIER)
+	header := "class " + stmt.Name.Lexeme
+	if stmt.Superclass != nil {
+		f.take(LESS)
+		f.take(IDENTIFIER)
+		header += " < " + stmt.Superclass.Name.Lexeme
+	}
+	f.take(LEFT_BRACE)
+	f.line(header + " {")
+
+	f.indent++
+	for _, method := range stmt.Methods {
+		f.leading()
+		err := f.function("", method)
+		if err != nil {
+			return err
+		}
+	}
+	f.close()
+	return nil
+}
+
+func (f *Formatter) VisitFunctionStmt(stmt FunctionStmt) error {
+	f.take(FUN)
+	return f.function("fun ", stmt)
+}
+
+func (f *Formatter) VisitVariableStmt(stmt VariableStmt) error {
+	return f.render(func() (string, error) {
+		return f.varDeclaration(stmt)
+	})
+}
+
+func (f *Formatter) VisitExpressionStmt(stmt ExpressionStmt) error {
+	return f.render(func() (string, error) {
+		return f.expressionStatement(stmt)
+	})
+}
+
+func (f *Formatter) VisitPrintStmt(stmt PrintStmt) error {
+	return f.render(func() (string, error) {
+		f.take(PRINT)
+		value, err := f.expr(stmt.Expr)
+		if err != nil {
+			return "", err
+		}
+		f.take(SEMICOLON)
+		return "print " + value + ";", nil
+	})
+}
+
+func (f *Formatter) VisitIfStmt(stmt IfStmt) error {
+	return f.ifStatement("", stmt)
+}
+
+func (f *Formatter) VisitWhileStmt(stmt WhileStmt) error {
+	if f.peek().Type == FOR {
+		return f.forStatement(nil, stmt)
+	}
+
+	var header string
+	err := f.render(func() (string, error) {
+		f.take(WHILE)
+		f.take(LEFT_PAREN)
+		condition, err := f.expr(stmt.Condition)
+		if err != nil {
+			return "", err
+		}
+		f.take(RIGHT_PAREN)
+		header = "while (" + condition + ")"
+		return header + f.opening(stmt.Body), nil
+	}, true)
+	if err != nil {
+		return err
+	}
+	return f.body(header, stmt.Body, true)
+}
+
+func (f *Formatter) VisitBlockStmt(stmt BlockStmt) error {
+	// for loops with an initializer are desugared into a block
+	if f.peek().Type == FOR {
+		return f.forStatement(stmt.Statements[0], stmt.Statements[1].(WhileStmt))
+	}
+
+	f.take(LEFT_BRACE)
+	f.line("{")
+	f.indent++
+	err := f.stmts(stmt.Statements)
+	if err != nil {
+		return err
+	}
+	f.close()
+	return nil
+}
+
+func (f *Formatter) VisitReturnStmt(stmt ReturnStmt) error {
+	return f.render(func() (string, error) {
+		f.take(RETURN)
+		if stmt.Value == nil {
+			f.take(SEMICOLON)
+			return "return;", nil
+		}
+		value, err := f.expr(stmt.Value)
+		if err != nil {
+			return "", err
+		}
+		f.take(SEMICOLON)
+		return "return " + value + ";", nil
+	})
+}
+
+func (f *Formatter) VisitBreakStmt(stmt BreakStmt) error {
+	f.take(BREAK)
+	f.take(SEMICOLON)
+	f.line("break;")
+	return nil
+}
+
+func (f *Formatter) VisitContinueStmt(stmt ContinueStmt) error {
+	f.take(CONTINUE)
+	f.take(SEMICOLON)
+	f.line("continue;")
+	return nil
+}
+
+// function writes a function declaration or, without the keyword, a method
+func (f *Formatter) function(keyword string, stmt FunctionStmt) error {
+	f.take(IDENTIFIER)
+	f.take(LEFT_PAREN)
+	params := make([]string, len(stmt.Params))
+	for i, param := range stmt.Params {
+		if i > 0 {
+			f.take(COMMA)
+		}
+		f.take(IDENTIFIER)
+		params[i] = param.Lexeme
+	}
+	f.take(RIGHT_PAREN)
+	f.take(LEFT_BRACE)
+	f.line(keyword + stmt.Name.Lexeme + "(" + strings.Join(params, ", ") + ") {")
+
+	f.indent++
+	err := f.stmts(stmt.Body)
+	if err != nil {
+		return err
+	}
+	f.close()
+	return nil
+}
+
+// ifStatement writes an if statement, prefix is set for the else branch of
+// a chain, eg. "} else "
+func (f *Formatter) ifStatement(prefix string, stmt IfStmt) error {
+	var header string
+	err := f.render(func() (string, error) {
+		f.take(IF)
+		f.take(LEFT_PAREN)
+		guard, err := f.expr(stmt.Guard)
+		if err != nil {
+			return "", err
+		}
+		f.take(RIGHT_PAREN)
+		header = prefix + "if (" + guard + ")"
+		return header + f.opening(stmt.ThenBranch), nil
+	}, true)
+	if err != nil {
+		return err
+	}
+
+	braced := f.braced(stmt.ThenBranch)
+	err = f.body(header, stmt.ThenBranch, stmt.ElseBranch == nil)
+	if err != nil || stmt.ElseBranch == nil {
+		return err
+	}
+
+	f.take(ELSE)
+	prefix = "else "
+	if braced {
+		prefix = "} else "
+	}
+	if elseIf, ok := stmt.ElseBranch.(IfStmt); ok && f.peek().Type == IF {
+		return f.ifStatement(prefix, elseIf)
+	}
+	header = strings.TrimSuffix(prefix, " ")
+	if f.braced(stmt.ElseBranch) {
+		f.take(LEFT_BRACE)
+		f.line(header + " {")
+		f.indent++
+		err := f.stmts(stmt.ElseBranch.(BlockStmt).Statements)
+		if err != nil {
+			return err
+		}
+		f.close()
+		return nil
+	}
+	return f.body(header, stmt.ElseBranch, true)
+}
+
+// forStatement puts a desugared for loop back together
+func (f *Formatter) forStatement(initializer Stmt, loop WhileStmt) error {
+	var header string
+	err := f.render(func() (string, error) {
+		f.take(FOR)
+		f.take(LEFT_PAREN)
+
+		clauses := ";"
+		var err error
+		switch initializer := initializer.(type) {
+		case VariableStmt:
+			clauses, err = f.varDeclaration(initializer)
+		case ExpressionStmt:
+			clauses, err = f.expressionStatement(initializer)
+		default:
+			f.take(SEMICOLON)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		// a missing condition was filled in with true by the parser
+		if f.peek().Type != SEMICOLON {
+			condition, err := f.expr(loop.Condition)
+			if err != nil {
+				return "", err
+			}
+			clauses += " " + condition
+		}
+		f.take(SEMICOLON)
+		clauses += ";"
+
+		if loop.Increment != nil {
+			increment, err := f.expr(loop.Increment)
+			if err != nil {
+				return "", err
+			}
+			clauses += " " + increment
+		}
+		f.take(RIGHT_PAREN)
+
+		header = "for (" + clauses + ")"
+		return header + f.opening(loop.Body), nil
+	}, true)
+	if err != nil {
+		return err
+	}
+	return f.body(header, loop.Body, true)
+}
+
+func (f *Formatter) varDeclaration(stmt VariableStmt) (string, error) {
+	f.take(VAR)
+	f.take(IDENTIFIER)
+	if stmt.Initializer == nil {
+		f.take(SEMICOLON)
+		return "var " + stmt.Name.Lexeme + ";", nil
+	}
+	f.take(EQUAL)
+	value, err := f.expr(stmt.Initializer)
+	if err != nil {
+		return "", err
+	}
+	f.take(SEMICOLON)
+	return "var " + stmt.Name.Lexeme + " = " + value + ";", nil
+}
+
+func (f *Formatter) expressionStatement(stmt ExpressionStmt) (string, error) {
+	value, err := f.expr(stmt.Expr)
+	if err != nil {
+		return "", err
+	}
+	f.take(SEMICOLON)
+	return value + ";", nil
+}
+
+// body writes the statement controlled by an if, else or loop header that
+// has already been rendered. A block opens on the header line, anything
+// else goes on the next line one level deeper. The closing brace is left
+// off when the caller follows it with else.
+func (f *Formatter) body(header string, stmt Stmt, closing bool) error {
+	if !f.braced(stmt) {
+		f.line(header)
+		f.indent++
+		defer func() { f.indent-- }()
+		f.leading()
+		return stmt.Accept(f)
+	}
+
+	f.take(LEFT_BRACE)
+	f.line(header + " {")
+	f.indent++
+	err := f.stmts(stmt.(BlockStmt).Statements)
+	if err != nil {
+		return err
+	}
+	if closing {
+		f.close()
+		return nil
+	}
+	f.leading()
+	f.take(RIGHT_BRACE)
+	f.indent--
+	return nil
+}
+
+// braced reports whether stmt is a block written with braces, rather than
+// one the parser made out of a for loop
+func (f *Formatter) braced(stmt Stmt) bool {
+	_, ok := stmt.(BlockStmt)
+	return ok && f.peek().Type == LEFT_BRACE
+}
+
+// opening is what a header line ends with, used to measure it
+func (f *Formatter) opening(stmt Stmt) string {
+	if f.braced(stmt) {
+		return " {"
+	}
+	return ""
+}
+
+// close writes the closing brace of a block whose statements were indented
+func (f *Formatter) close() {
+	f.leading()
+	f.take(RIGHT_BRACE)
+	f.indent--
+	f.line("}")
+}
+
+func (f *Formatter) stmts(stmts []Stmt) error {
+	for _, stmt := range stmts {
+		f.leading()
+		err := stmt.Accept(f)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Formatter) binary(left Expr, op Token, right Expr) (any, error) {
+	l, err := f.expr(left)
+	if err != nil {
+		return nil, err
+	}
+	f.take(op.Type)
+	r, err := f.expr(right)
+	if err != nil {
+		return nil, err
+	}
+	return l + " " + op.Lexeme + " " + r, nil
+}
+
+func (f *Formatter) expr(expr Expr) (string, error) {
+	s, err := expr.Accept(f)
+	if err != nil {
+		return "", err
+	}
+	return s.(string), nil
+}
+
+// render writes the line built by fn. If it's too wide fn is run again from
+// the same token with call arguments wrapped. Headers only measure the line
+// and leave writing it to the caller.
+func (f *Formatter) render(fn func() (string, error), header ...bool) error {
+	current, comments := f.current, len(f.comments)
+	s, err := fn()
+	if err != nil {
+		return err
+	}
+	if f.width(s) > maxWidth {
+		f.current, f.comments = current, f.comments[:comments]
+		f.wrap = true
+		s, err = fn()
+		f.wrap = false
+		if err != nil {
+			return err
+		}
+	}
+	if len(header) == 0 {
+		f.line(s)
+	}
+	return nil
+}
+
+func (f *Formatter) width(s string) int {
+	widest := 0
+	for i, line := range strings.Split(s, "\n") {
+		width := utf8.RuneCountInString(line)
+		if i == 0 {
+			width += 2 * f.indent
+		}
+		widest = max(widest, width)
+	}
+	return widest
+}
+
+// take moves the cursor past the next token of one of the given types and
+// returns it, collecting the comments on it and any tokens skipped over
+func (f *Formatter) take(types ...TokenType) Token {
+	for i := f.current; i < len(f.tokens); i++ {
+		token := f.tokens[i]
+		for _, t := range types {
+			if token.Type != t {
+				continue
+			}
+			for _, skipped := range f.tokens[f.current : i+1] {
+				f.comments = append(f.comments, skipped.Comments...)
+			}
+			f.current = i + 1
+			return token
+		}
+	}
+	return Token{}
+}
+
+func (f *Formatter) peek() Token {
+	if f.current >= len(f.tokens) {
+		return Token{Type: EOF}
+	}
+	return f.tokens[f.current]
+}
+
+// leading writes the comments and blank line in front of the next token.
+// Trailing comments end the line already written instead.
+func (f *Formatter) leading() {
+	if f.current >= len(f.tokens) {
+		return
+	}
+	token := &f.tokens[f.current]
+	for _, comment := range token.Comments {
+		if comment.Trailing && len(f.lines) > 0 {
+			f.lines[len(f.lines)-1] += " " + comment.Text
+			continue
+		}
+		if comment.BlankLine {
+			f.blank()
+		}
+		f.write(comment.Text)
+	}
+	if token.BlankLine {
+		f.blank()
+	}
+	token.Comments, token.BlankLine = nil, false
+}
+
+// line writes s with the comments picked up while rendering it, leading
+// comments go above it and trailing ones at the end
+func (f *Formatter) line(s string) {
+	var trailing []string
+	for _, comment := range f.comments {
+		if comment.Trailing {
+			trailing = append(trailing, comment.Text)
+		} else {
+			f.write(comment.Text)
+		}
+	}
+	f.comments = f.comments[:0]
+	f.write(strings.Join(append([]string{s}, trailing...), " "))
+}
+
+func (f *Formatter) write(s string) {
+	f.lines = append(f.lines, strings.Repeat("  ", f.indent)+s)
+}
+
+// blank separates statements with one empty line, but never at the start
+// of the file or a block
+func (f *Formatter) blank() {
+	if len(f.lines) == 0 {
+		return
+	}
+	last := f.lines[len(f.lines)-1]
+	if last == "" || strings.HasSuffix(last, "{") {
+		return
+	}
+	f.lines = append(f.lines, "")
+}
@@ -0,0 +1,56 @@
+package lox
+
+type LoxFunction struct {
+	declaration   FunctionStmt
+	closure       *Environment
+	isInitializer bool
+}
+
+func NewLoxFunction(declaration FunctionStmt, closure *Environment, isInitializer bool) *LoxFunction {
+	return &LoxFunction{declaration: declaration, closure: closure, isInitializer: isInitializer}
+}
+
+// bind returns a copy of the method whose closure has "this" set to instance
+func (f *LoxFunction) bind(instance *LoxInstance) *LoxFunction {
+	env := NewNestedEnvironment(f.closure)
+	env.define("this", instance)
+	return NewLoxFunction(f.declaration, env, f.isInitializer)
+}
+
+func (f *LoxFunction) Arity() int { return len(f.declaration.Params) }
+
+func (f *LoxFunction) Call(interpreter *Interpreter, args []Value) (Value, error) {
+	// each call gets its own environment so recursion doesn't clobber params
+	env := NewNestedEnvironment(f.closure)
+	for i, param := range f.declaration.Params {
+		env.define(param.Lexeme, args[i])
+	}
+
+	err := interpreter.executeBlock(f.declaration.Body, env)
+	if err != nil {
+		ret, ok := err.(ReturnSignal)
+		if !ok {
+			return nil, err
+		}
+		if !f.isInitializer {
+			return ret.Value, nil
+		}
+	}
+
+	// init() always hands back the instance, even on a bare `return;`
+	if f.isInitializer {
+		return f.closure.getAt(0, "this"), nil
+	}
+	return Nil{}, nil
+}
+
+func (f *LoxFunction) Kind() Kind     { return FunctionKind }
+func (f *LoxFunction) String() string { return "<fn " + f.declaration.Name.Lexeme + ">" }
+func (f *LoxFunction) Truthy() bool   { return true }
+
+// functions are only equal to themselves, so each binding of a method is a
+// different function
+func (f *LoxFunction) Equals(other Value) bool {
+	o, ok := other.(*LoxFunction)
+	return ok && f == o
+}
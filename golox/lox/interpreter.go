@@ -0,0 +1,549 @@
+package lox
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"time"
+)
+
+// deep enough for real recursion, shallow enough to stop before Go's stack does
+const maxCallDepth = 2048
+
+// Frame is one active call of a Lox function or class
+type Frame struct {
+	Function string
+	Call     Token // closing paren of the call expression
+}
+
+type Interpreter struct {
+	Globals *Environment
+
+	environment *Environment
+	out         io.Writer
+
+	// runtime errors are added here as well as returned
+	diagnostics *Diagnostics
+
+	// scope distance of each local variable reference, filled by the Resolver
+	locals map[Expr]int
+
+	// active calls, innermost last, used for stack traces
+	frames []Frame
+
+	// state for the input and random natives
+	stdin  *bufio.Reader
+	random *rand.Rand
+}
+
+func NewInterpreter() *Interpreter {
+	globals := NewEnvironment()
+
+	globals.define("clock", ClockNativeFn{})
+	for _, native := range natives {
+		globals.define(native.name, native)
+	}
+
+	return &Interpreter{
+		environment: globals,
+		Globals:     globals,
+		out:         os.Stdout,
+		diagnostics: NewDiagnostics(),
+		locals:      make(map[Expr]int),
+		stdin:       bufio.NewReader(os.Stdin),
+		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Interpret runs statements and returns the value of the last one if it is
+// an expression statement, nil otherwise
+func (i *Interpreter) Interpret(statements []Stmt) (Value, error) {
+	var value Value = Nil{}
+	for _, statement := range statements {
+		var err error
+		if stmt, ok := statement.(ExpressionStmt); ok {
+			value, err = i.evaluate(stmt.Expr)
+		} else {
+			value, err = Nil{}, i.execute(statement)
+		}
+		if err != nil {
+			i.frames = i.frames[:0]
+			i.diagnostics.Add(err)
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func (i *Interpreter) resolve(expr Expr, depth int) {
+	i.locals[expr] = depth
+}
+
+func (i *Interpreter) VisitClassStmt(stmt ClassStmt) error {
+	var superclass *LoxClass = nil
+	if stmt.Superclass != nil {
+		value, err := i.evaluate(stmt.Superclass)
+		if err != nil {
+			return err
+		}
+		class, ok := value.(*LoxClass)
+		if !ok {
+			return NewRuntimeError(stmt.Superclass.Name, "Superclass must be a class.")
+		}
+		superclass = class
+	}
+
+	i.environment.define(stmt.Name.Lexeme, Nil{})
+
+	// methods close over an extra scope holding "super"
+	closure := i.environment
+	if superclass != nil {
+		closure = NewNestedEnvironment(i.environment)
+		closure.define("super", superclass)
+	}
+
+	methods := make(map[string]*LoxFunction)
+	for _, method := range stmt.Methods {
+		methods[method.Name.Lexeme] = NewLoxFunction(method, closure, method.Name.Lexeme == "init")
+	}
+
+	class := NewLoxClass(stmt.Name.Lexeme, superclass, methods)
+	return i.environment.assign(stmt.Name, class)
+}
+
+func (i *Interpreter) VisitFunctionStmt(stmt FunctionStmt) error {
+	function := NewLoxFunction(stmt, i.environment, false)
+	i.environment.define(stmt.Name.Lexeme, function)
+	return nil
+}
+
+func (i *Interpreter) VisitVariableStmt(stmt VariableStmt) error {
+	var value Value = Nil{}
+	var err error
+
+	if stmt.Initializer != nil {
+		value, err = i.evaluate(stmt.Initializer)
+		if err != nil {
+			return err
+		}
+	}
+
+	i.environment.define(stmt.Name.Lexeme, value)
+	return nil
+}
+
+func (i *Interpreter) VisitExpressionStmt(stmt ExpressionStmt) error {
+	_, err := i.evaluate(stmt.Expr)
+	return err
+}
+
+func (i *Interpreter) VisitIfStmt(stmt IfStmt) error {
+	value, err := i.evaluate(stmt.Guard)
+	if err != nil {
+		return err
+	}
+
+	if value.Truthy() {
+		return i.execute(stmt.ThenBranch)
+	} else if stmt.ElseBranch != nil {
+		return i.execute(stmt.ElseBranch)
+	}
+	return nil
+}
+
+func (i *Interpreter) VisitPrintStmt(stmt PrintStmt) error {
+	value, err := i.evaluate(stmt.Expr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(i.out, value.String())
+	return nil
+}
+
+func (i *Interpreter) VisitWhileStmt(stmt WhileStmt) error {
+	condition, err := i.evaluate(stmt.Condition)
+	if err != nil {
+		return err
+	}
+	for condition.Truthy() {
+		err := i.execute(stmt.Body)
+		if err != nil {
+			if _, ok := err.(BreakSignal); ok {
+				return nil
+			}
+			if _, ok := err.(ContinueSignal); !ok {
+				return err
+			}
+		}
+		if stmt.Increment != nil {
+			_, err = i.evaluate(stmt.Increment)
+			if err != nil {
+				return err
+			}
+		}
+		condition, err = i.evaluate(stmt.Condition)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Interpreter) VisitBlockStmt(stmt BlockStmt) error {
+	return i.executeBlock(stmt.Statements, NewNestedEnvironment(i.environment))
+}
+
+func (i *Interpreter) VisitReturnStmt(stmt ReturnStmt) error {
+	var value Value = Nil{}
+	var err error
+
+	if stmt.Value != nil {
+		value, err = i.evaluate(stmt.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ReturnSignal{Value: value}
+}
+
+func (i *Interpreter) VisitBreakStmt(stmt BreakStmt) error {
+	return BreakSignal{}
+}
+
+func (i *Interpreter) VisitContinueStmt(stmt ContinueStmt) error {
+	return ContinueSignal{}
+}
+
+func (i *Interpreter) executeBlock(stmts []Stmt, env *Environment) error {
+	previous := i.environment
+	i.environment = env
+	defer func() { i.environment = previous }()
+
+	for _, stmt := range stmts {
+		err := i.execute(stmt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Interpreter) VisitAssignmentExpr(expr *AssignmentExpr) (any, error) {
+	value, err := i.evaluate(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+	if distance, ok := i.locals[expr]; ok {
+		i.environment.assignAt(distance, expr.Name, value)
+		return value, nil
+	}
+
+	err = i.Globals.assign(expr.Name, value)
+	if err != nil {
+		return nil, NewRuntimeError(expr.Name, err.Error())
+	}
+	return value, nil
+}
+
+func (i *Interpreter) VisitConditionalExpr(expr *ConditionalExpr) (any, error) {
+	condition, err := i.evaluate(expr.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the chosen branch is evaluated
+	if condition.Truthy() {
+		return i.evaluate(expr.Then)
+	}
+	return i.evaluate(expr.Otherwise)
+}
+
+func (i *Interpreter) VisitLogicalExpr(expr *LogicalExpr) (any, error) {
+	left, err := i.evaluate(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	// short circuit
+	if expr.Op.Type == OR {
+		if left.Truthy() {
+			return left, nil
+		}
+	} else {
+		if !left.Truthy() {
+			return left, nil
+		}
+	}
+
+	return i.evaluate(expr.Right)
+}
+
+func (i *Interpreter) VisitBinaryExpr(expr *BinaryExpr) (any, error) {
+	left, err := i.evaluate(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := i.evaluate(expr.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expr.Op.Type {
+	case SLASH:
+		leftNum, leftOk := left.(Number)
+		rightNum, rightOk := right.(Number)
+		if !leftOk || !rightOk {
+			return nil, NewRuntimeError(expr.Op, "Operands must be numbers.")
+		}
+		if rightNum == 0 {
+			return nil, NewRuntimeError(expr.Op, "Division by zero.")
+		}
+		return leftNum / rightNum, nil
+	case MINUS:
+		leftNum, leftOk := left.(Number)
+		rightNum, rightOk := right.(Number)
+		if !leftOk || !rightOk {
+			return nil, NewRuntimeError(expr.Op, "Operands must be numbers.")
+		}
+		return leftNum - rightNum, nil
+	case STAR:
+		leftNum, leftOk := left.(Number)
+		rightNum, rightOk := right.(Number)
+		if !leftOk || !rightOk {
+			return nil, NewRuntimeError(expr.Op, "Operands must be numbers.")
+		}
+		return leftNum * rightNum, nil
+	// use + for string concat and number addition
+	case PLUS:
+		switch left := left.(type) {
+		case Number:
+			switch right := right.(type) {
+			case String:
+				return String(left.String()) + right, nil
+			case Number:
+				return left + right, nil
+			}
+		case String:
+			return left + String(right.String()), nil
+		}
+		return nil, NewRuntimeError(expr.Op, "Operands must be two numbers or two strings.")
+
+	// only supported between numbers
+	case LESS:
+		leftNum, leftOk := left.(Number)
+		rightNum, rightOk := right.(Number)
+		if !leftOk || !rightOk {
+			return nil, NewRuntimeError(expr.Op, "Operands must be numbers.")
+		}
+		return Bool(leftNum < rightNum), nil
+	case LESS_EQUAL:
+		leftNum, leftOk := left.(Number)
+		rightNum, rightOk := right.(Number)
+		if !leftOk || !rightOk {
+			return nil, NewRuntimeError(expr.Op, "Operands must be numbers.")
+		}
+		return Bool(leftNum <= rightNum), nil
+	case GREATER:
+		leftNum, leftOk := left.(Number)
+		rightNum, rightOk := right.(Number)
+		if !leftOk || !rightOk {
+			return nil, NewRuntimeError(expr.Op, "Operands must be numbers.")
+		}
+		return Bool(leftNum > rightNum), nil
+	case GREATER_EQUAL:
+		leftNum, leftOk := left.(Number)
+		rightNum, rightOk := right.(Number)
+		if !leftOk || !rightOk {
+			return nil, NewRuntimeError(expr.Op, "Operands must be numbers.")
+		}
+		return Bool(leftNum >= rightNum), nil
+
+	case EQUAL_EQUAL:
+		return Bool(left.Equals(right)), nil
+	case BANG_EQUAL:
+		return Bool(!left.Equals(right)), nil
+	}
+
+	return Nil{}, nil
+}
+
+func (i *Interpreter) VisitGroupingExpr(expr *GroupingExpr) (any, error) {
+	return i.evaluate(expr.Expr)
+}
+
+func (i *Interpreter) VisitUnaryExpr(expr *UnaryExpr) (any, error) {
+	v, err := i.evaluate(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expr.Op.Type {
+	case BANG:
+		return Bool(!v.Truthy()), nil
+	case MINUS:
+		if num, ok := v.(Number); ok {
+			return -num, nil
+		}
+		return nil, NewRuntimeError(expr.Op, "Operand must be a number.")
+	}
+
+	return Nil{}, nil
+}
+
+func (i *Interpreter) VisitCallExpr(expr *CallExpr) (any, error) {
+	callee, err := i.evaluate(expr.Callee)
+	if err != nil {
+		return nil, err
+	}
+
+	var args []Value
+	for _, arg := range expr.Args {
+		evalArg, err := i.evaluate(arg)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, evalArg)
+	}
+
+	function, ok := callee.(Callable)
+	if !ok {
+		return nil, NewRuntimeError(expr.Paren, "Can only call functions and classes.")
+	}
+
+	arity := function.Arity()
+	if arity != len(args) {
+		return nil, NewRuntimeError(expr.Paren, fmt.Sprintf("Expected %d arguments but got %d.", arity, len(args)))
+	}
+
+	name, isLox := frameName(function)
+	if isLox {
+		if len(i.frames) >= maxCallDepth {
+			return nil, NewRuntimeError(expr.Paren, "Stack overflow.")
+		}
+		i.frames = append(i.frames, Frame{Function: name, Call: expr.Paren})
+		defer func() { i.frames = i.frames[:len(i.frames)-1] }()
+	}
+
+	result, err := function.Call(i, args)
+	if err != nil {
+		switch e := err.(type) {
+		case RuntimeError:
+			// the innermost call records the trace, outer calls pass it on
+			if e.Trace == nil && isLox {
+				e.Trace = slices.Clone(i.frames)
+			}
+			return nil, e
+		case LoxError:
+			return nil, err
+		}
+		// natives report misuse with plain errors, pin them to the call
+		return nil, NewRuntimeError(expr.Paren, err.Error())
+	}
+	return result, nil
+}
+
+// frameName names the calls that get a stack frame. Natives don't, an error
+// in one is reported at the call site in Lox code.
+func frameName(function Callable) (string, bool) {
+	switch function := function.(type) {
+	case *LoxFunction:
+		return function.declaration.Name.Lexeme, true
+	case *LoxClass:
+		return function.Name, true
+	}
+	return "", false
+}
+
+func (i *Interpreter) VisitLiteralExpr(expr *LiteralExpr) (any, error) {
+	value, _ := valueOf(expr.Value)
+	return value, nil
+}
+
+func (i *Interpreter) VisitGetExpr(expr *GetExpr) (any, error) {
+	object, err := i.evaluate(expr.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	// instances and native objects like lists both expose properties
+	holder, ok := object.(interface {
+		Get(name Token) (Value, error)
+	})
+	if !ok {
+		return nil, NewRuntimeError(expr.Name, "Only instances have properties.")
+	}
+	return holder.Get(expr.Name)
+}
+
+func (i *Interpreter) VisitSetExpr(expr *SetExpr) (any, error) {
+	object, err := i.evaluate(expr.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := object.(*LoxInstance)
+	if !ok {
+		return nil, NewRuntimeError(expr.Name, "Only instances have fields.")
+	}
+
+	value, err := i.evaluate(expr.Value)
+	if err != nil {
+		return nil, err
+	}
+	instance.Set(expr.Name, value)
+	return value, nil
+}
+
+func (i *Interpreter) VisitSuperExpr(expr *SuperExpr) (any, error) {
+	// "this" always lives in the scope just inside the one holding "super"
+	distance := i.locals[expr]
+	superclass := i.environment.getAt(distance, "super").(*LoxClass)
+	this := i.environment.getAt(distance-1, "this")
+
+	method, ok := superclass.findMethod(expr.Method.Lexeme)
+	if !ok {
+		return nil, NewRuntimeError(expr.Method, "Undefined property '"+expr.Method.Lexeme+"'.")
+	}
+	return method.bind(this.(*LoxInstance)), nil
+}
+
+func (i *Interpreter) VisitThisExpr(expr *ThisExpr) (any, error) {
+	return i.lookUpVariable(expr.Keyword, expr)
+}
+
+func (i *Interpreter) VisitVariableExpr(expr *VariableExpr) (any, error) {
+	return i.lookUpVariable(expr.Name, expr)
+}
+
+func (i *Interpreter) lookUpVariable(name Token, expr Expr) (Value, error) {
+	if distance, ok := i.locals[expr]; ok {
+		return i.environment.getAt(distance, name.Lexeme), nil
+	}
+
+	value, err := i.Globals.get(name)
+	if err != nil {
+		return nil, NewRuntimeError(name, err.Error())
+	}
+	return value, nil
+}
+
+func (i *Interpreter) evaluate(expr Expr) (Value, error) {
+	result, err := expr.Accept(i)
+	if err != nil {
+		return nil, err
+	}
+	// a Go nil would panic as soon as it is used, Lox's nil is Nil{}
+	value, ok := result.(Value)
+	if !ok || value == nil {
+		return nil, fmt.Errorf("%T evaluated to no value", expr)
+	}
+	return value, nil
+}
+
+func (i *Interpreter) execute(stmt Stmt) error {
+	return stmt.Accept(i)
+}
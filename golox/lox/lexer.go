@@ -1,46 +1,66 @@
-package main
+package lox
 
-import "strconv"
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
 
 var keywords = map[string]TokenType{
-	"and":    AND,
-	"or":     OR,
-	"if":     IF,
-	"else":   ELSE,
-	"true":   TRUE,
-	"false":  FALSE,
-	"for":    FOR,
-	"while":  WHILE,
-	"nil":    NIL,
-	"print":  PRINT,
-	"return": RETURN,
-	"fun":    FUN,
-	"class":  CLASS,
-	"var":    VAR,
-	"super":  SUPER,
-	"this":   THIS,
+	"and":      AND,
+	"or":       OR,
+	"if":       IF,
+	"else":     ELSE,
+	"true":     TRUE,
+	"false":    FALSE,
+	"for":      FOR,
+	"while":    WHILE,
+	"nil":      NIL,
+	"print":    PRINT,
+	"return":   RETURN,
+	"fun":      FUN,
+	"class":    CLASS,
+	"var":      VAR,
+	"super":    SUPER,
+	"this":     THIS,
+	"break":    BREAK,
+	"continue": CONTINUE,
 }
 
 type Lexer struct {
 	source string
+	file   *Source
 	tokens []Token
 
 	start, current, line int
 	errors               []error
-	reporter             *ErrorReporter
+	diagnostics          *Diagnostics
+
+	// byte offset where the current line begins, for computing columns
+	lineStart int
+	// position of the token being scanned, captured before it may span lines
+	startLine, startColumn int
+
+	// trivia waiting to be attached to the next token, newlines counts line
+	// breaks since the last token or comment
+	comments []Comment
+	newlines int
 }
 
-func NewLexer(source string) *Lexer {
-	return &Lexer{source: source, reporter: NewErrorReporter(), errors: make([]error, 0), line: 1}
+func NewLexer(source string, diagnostics *Diagnostics) *Lexer {
+	return &Lexer{source: source, file: &Source{Text: source}, diagnostics: diagnostics, errors: make([]error, 0), line: 1}
 }
 
 func (s *Lexer) ScanTokens() ([]Token, []error) {
 	for !s.isAtEnd() {
 		s.start = s.current
+		s.startLine, s.startColumn = s.line, s.column(s.start)
 		s.scanToken()
 	}
 
-	s.tokens = append(s.tokens, Token{Type: EOF, Lexeme: "", Literal: nil, Line: s.line})
+	s.start = s.current
+	s.startLine, s.startColumn = s.line, s.column(s.start)
+	s.addToken(EOF, nil)
 	return s.tokens, s.errors
 }
 
@@ -103,6 +123,7 @@ func (s *Lexer) scanToken() {
 			for s.peek() != '\n' && !s.isAtEnd() {
 				s.advance()
 			}
+			s.addComment()
 		} else {
 			s.addToken(SLASH, nil)
 		}
@@ -115,7 +136,8 @@ func (s *Lexer) scanToken() {
 	case ' ', '\t', '\r':
 
 	case '\n':
-		s.line++
+		s.newline()
+		s.newlines++
 
 	default:
 		// number literals
@@ -124,13 +146,23 @@ func (s *Lexer) scanToken() {
 		} else if isAlpha(c) {
 			s.scanIdentifier()
 		} else {
-			err := NewLexerError(s.line, "Unexpected character: '"+string(c)+"'")
-			s.reporter.Report(err)
-			s.errors = append(s.errors, err)
+			s.unexpectedCharacter()
 		}
 	}
 }
 
+// unexpectedCharacter reports the whole character at start, which may be
+// several bytes long, so each one is reported once at its own column
+func (s *Lexer) unexpectedCharacter() {
+	r, size := utf8.DecodeRuneInString(s.source[s.start:])
+	s.current = s.start + size
+	if r == utf8.RuneError && size == 1 {
+		s.error(NewLexerError(s.span(), fmt.Sprintf("Invalid UTF-8 byte: 0x%02x", s.source[s.start])))
+		return
+	}
+	s.error(NewLexerError(s.span(), "Unexpected character: '"+string(r)+"'", unexpectedCharacterNotes(r)...))
+}
+
 func (s *Lexer) scanIdentifier() {
 	for isAlphaNumeric(s.peek()) {
 		s.advance()
@@ -147,15 +179,15 @@ func (s *Lexer) scanIdentifier() {
 func (s *Lexer) scanString() {
 	for s.peek() != '"' && !s.isAtEnd() {
 		if s.peek() == '\n' {
-			s.line++
+			s.advance()
+			s.newline()
+			continue
 		}
 		s.advance()
 	}
 
 	if s.isAtEnd() {
-		err := NewLexerError(s.line, "Unterminated string")
-		s.reporter.Report(err)
-		s.errors = append(s.errors, err)
+		s.error(NewLexerError(s.span(), "Unterminated string", "add a closing '\"' to end the string"))
 		return
 	}
 
@@ -181,18 +213,59 @@ func (s *Lexer) scanNumber() {
 
 	value, err := strconv.ParseFloat((s.source[s.start:s.current]), 10)
 	if err != nil {
-		err2 := NewLexerError(s.line, "Invalid number: "+s.source[s.start:s.current])
-		s.reporter.Report(err2)
-		s.errors = append(s.errors, err2)
+		s.error(NewLexerError(s.span(), "Invalid number: "+s.source[s.start:s.current]))
 		return
 	}
 	s.addToken(NUMBER, value)
 }
 
+func (s *Lexer) error(err LexerError) {
+	s.diagnostics.Add(err)
+	s.errors = append(s.errors, err)
+}
+
 // create and add token, start to current, to tokens
 func (s *Lexer) addToken(tok TokenType, literal any) {
 	text := string(s.source[s.start:s.current])
-	s.tokens = append(s.tokens, Token{Type: tok, Lexeme: text, Literal: literal, Line: s.line})
+	token := NewToken(tok, text, literal, s.span())
+	token.Comments, token.BlankLine = s.comments, s.newlines > 1
+	s.tokens = append(s.tokens, token)
+	s.comments, s.newlines = nil, 0
+}
+
+// comments are trivia, they are held until the next token is added
+func (s *Lexer) addComment() {
+	comment := Comment{
+		Text:      string(s.source[s.start:s.current]),
+		Trailing:  s.newlines == 0 && len(s.tokens) > 0,
+		BlankLine: s.newlines > 1,
+	}
+	s.comments = append(s.comments, comment)
+	s.newlines = 0
+}
+
+// span covers start to current
+func (s *Lexer) span() Span {
+	return Span{
+		Line:      s.startLine,
+		Column:    s.startColumn,
+		EndLine:   s.line,
+		EndColumn: s.column(s.current),
+		Offset:    s.start,
+		Length:    s.current - s.start,
+		Source:    s.file,
+	}
+}
+
+// column of the byte at offset, which must be on the current line
+func (s *Lexer) column(offset int) int {
+	return utf8.RuneCountInString(s.source[s.lineStart:offset]) + 1
+}
+
+// call after consuming a '\n'
+func (s *Lexer) newline() {
+	s.line++
+	s.lineStart = s.current
 }
 
 // consumes character iff current matches expected
@@ -233,6 +306,21 @@ func (s *Lexer) isAtEnd() bool {
 	return s.current >= len(s.source)
 }
 
+// hints for characters people commonly bring over from other languages
+func unexpectedCharacterNotes(c rune) []string {
+	switch c {
+	case '&':
+		return []string{"use `and` for logical and"}
+	case '|':
+		return []string{"use `or` for logical or"}
+	case '%':
+		return []string{"there is no modulo operator"}
+	case '\'':
+		return []string{"strings use double quotes"}
+	}
+	return nil
+}
+
 func isAlpha(c byte) bool {
 	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
 }
@@ -0,0 +1,72 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoxList is the value returned by natives like split and the `args`
+// global. It has no literal syntax and is read only, its elements are reached
+// through two methods: get and length.
+type LoxList struct {
+	elements []Value
+}
+
+func NewLoxList(elements []Value) *LoxList {
+	return &LoxList{elements: elements}
+}
+
+func (l *LoxList) Get(name Token) (Value, error) {
+	switch name.Lexeme {
+	case "get":
+		return NewNativeFn("get", 1, func(interpreter *Interpreter, args []Value) (Value, error) {
+			i, err := intArg("get", args, 0)
+			if err != nil {
+				return nil, err
+			}
+			if i < 0 || i >= len(l.elements) {
+				return nil, fmt.Errorf("get() index %d out of bounds for list of length %d.", i, len(l.elements))
+			}
+			return l.elements[i], nil
+		}), nil
+	case "length":
+		return NewNativeFn("length", 0, func(interpreter *Interpreter, args []Value) (Value, error) {
+			return Number(len(l.elements)), nil
+		}), nil
+	}
+
+	return nil, NewRuntimeError(name, "Undefined property '"+name.Lexeme+"'.")
+}
+
+func (l *LoxList) String() string {
+	return l.format(make(map[*LoxList]bool))
+}
+
+// format prints a list that contains itself, which an embedder can build
+// with NewLoxList, as [...] where it repeats instead of recursing forever
+func (l *LoxList) format(printing map[*LoxList]bool) string {
+	if printing[l] {
+		return "[...]"
+	}
+	printing[l] = true
+	defer delete(printing, l)
+
+	parts := make([]string, len(l.elements))
+	for i, element := range l.elements {
+		if list, ok := element.(*LoxList); ok {
+			parts[i] = list.format(printing)
+		} else {
+			parts[i] = element.String()
+		}
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func (l *LoxList) Kind() Kind   { return ListKind }
+func (l *LoxList) Truthy() bool { return true }
+
+// lists are compared by identity, not by their elements
+func (l *LoxList) Equals(other Value) bool {
+	o, ok := other.(*LoxList)
+	return ok && l == o
+}
@@ -0,0 +1,261 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// NativeFn wraps a Go function so it can be called from Lox. Errors returned
+// by fn are turned into runtime errors at the call site by the interpreter.
+type NativeFn struct {
+	name  string
+	arity int
+	fn    func(interpreter *Interpreter, args []Value) (Value, error)
+}
+
+func NewNativeFn(name string, arity int, fn func(interpreter *Interpreter, args []Value) (Value, error)) *NativeFn {
+	return &NativeFn{name: name, arity: arity, fn: fn}
+}
+
+func (n *NativeFn) Arity() int { return n.arity }
+
+func (n *NativeFn) Call(interpreter *Interpreter, args []Value) (Value, error) {
+	return n.fn(interpreter, args)
+}
+
+func (n *NativeFn) Kind() Kind     { return FunctionKind }
+func (n *NativeFn) String() string { return "<native fn>" }
+func (n *NativeFn) Truthy() bool   { return true }
+
+func (n *NativeFn) Equals(other Value) bool {
+	o, ok := other.(*NativeFn)
+	return ok && n == o
+}
+
+var natives = []*NativeFn{
+	// strings
+	NewNativeFn("len", 1, nativeLen),
+	NewNativeFn("substr", 3, nativeSubstr),
+	NewNativeFn("upper", 1, nativeUpper),
+	NewNativeFn("lower", 1, nativeLower),
+	NewNativeFn("split", 2, nativeSplit),
+	NewNativeFn("indexOf", 2, nativeIndexOf),
+
+	// math
+	NewNativeFn("sqrt", 1, nativeSqrt),
+	NewNativeFn("floor", 1, nativeFloor),
+	NewNativeFn("pow", 2, nativePow),
+	NewNativeFn("abs", 1, nativeAbs),
+	NewNativeFn("random", 0, nativeRandom),
+	NewNativeFn("seed", 1, nativeSeed),
+
+	// types and conversions
+	NewNativeFn("type", 1, nativeType),
+	NewNativeFn("str", 1, nativeStr),
+	NewNativeFn("num", 1, nativeNum),
+
+	// io
+	NewNativeFn("input", 0, nativeInput),
+}
+
+func nativeLen(interpreter *Interpreter, args []Value) (Value, error) {
+	switch arg := args[0].(type) {
+	case String:
+		return Number(utf8.RuneCountInString(string(arg))), nil
+	case *LoxList:
+		return Number(len(arg.elements)), nil
+	}
+	return nil, fmt.Errorf("len() expects a string or list but got %s.", args[0].Kind())
+}
+
+func nativeSubstr(interpreter *Interpreter, args []Value) (Value, error) {
+	s, err := stringArg("substr", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	start, err := intArg("substr", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	end, err := intArg("substr", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(s)
+	if start < 0 || end > len(runes) || start > end {
+		return nil, fmt.Errorf("substr() range [%d, %d) out of bounds for string of length %d.", start, end, len(runes))
+	}
+	return String(runes[start:end]), nil
+}
+
+func nativeUpper(interpreter *Interpreter, args []Value) (Value, error) {
+	s, err := stringArg("upper", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return String(strings.ToUpper(s)), nil
+}
+
+func nativeLower(interpreter *Interpreter, args []Value) (Value, error) {
+	s, err := stringArg("lower", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return String(strings.ToLower(s)), nil
+}
+
+func nativeSplit(interpreter *Interpreter, args []Value) (Value, error) {
+	s, err := stringArg("split", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	sep, err := stringArg("split", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(s, sep)
+	elements := make([]Value, len(parts))
+	for i, part := range parts {
+		elements[i] = String(part)
+	}
+	return NewLoxList(elements), nil
+}
+
+func nativeIndexOf(interpreter *Interpreter, args []Value) (Value, error) {
+	s, err := stringArg("indexOf", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := stringArg("indexOf", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// index in characters, not bytes, to agree with len and substr
+	i := strings.Index(s, sub)
+	if i < 0 {
+		return Number(-1), nil
+	}
+	return Number(utf8.RuneCountInString(s[:i])), nil
+}
+
+func nativeSqrt(interpreter *Interpreter, args []Value) (Value, error) {
+	n, err := numberArg("sqrt", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("sqrt() of negative number %s.", Number(n))
+	}
+	return Number(math.Sqrt(n)), nil
+}
+
+func nativeFloor(interpreter *Interpreter, args []Value) (Value, error) {
+	n, err := numberArg("floor", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return Number(math.Floor(n)), nil
+}
+
+func nativePow(interpreter *Interpreter, args []Value) (Value, error) {
+	base, err := numberArg("pow", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := numberArg("pow", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return Number(math.Pow(base, exp)), nil
+}
+
+func nativeAbs(interpreter *Interpreter, args []Value) (Value, error) {
+	n, err := numberArg("abs", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return Number(math.Abs(n)), nil
+}
+
+func nativeRandom(interpreter *Interpreter, args []Value) (Value, error) {
+	return Number(interpreter.random.Float64()), nil
+}
+
+func nativeSeed(interpreter *Interpreter, args []Value) (Value, error) {
+	n, err := intArg("seed", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	interpreter.random = rand.New(rand.NewSource(int64(n)))
+	return Nil{}, nil
+}
+
+func nativeType(interpreter *Interpreter, args []Value) (Value, error) {
+	return String(args[0].Kind().String()), nil
+}
+
+func nativeStr(interpreter *Interpreter, args []Value) (Value, error) {
+	return String(args[0].String()), nil
+}
+
+func nativeNum(interpreter *Interpreter, args []Value) (Value, error) {
+	switch arg := args[0].(type) {
+	case Number:
+		return arg, nil
+	case Bool:
+		if arg {
+			return Number(1), nil
+		}
+		return Number(0), nil
+	case String:
+		n, err := strconv.ParseFloat(strings.TrimSpace(string(arg)), 64)
+		if err != nil {
+			return nil, fmt.Errorf("num() can't convert \"%s\" to a number.", arg)
+		}
+		return Number(n), nil
+	}
+	return nil, fmt.Errorf("num() expects a string, number or boolean but got %s.", args[0].Kind())
+}
+
+// input reads one line from stdin without the trailing newline, nil at EOF
+func nativeInput(interpreter *Interpreter, args []Value) (Value, error) {
+	line, err := interpreter.stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return Nil{}, nil
+	}
+	return String(strings.TrimRight(line, "\r\n")), nil
+}
+
+func stringArg(fn string, args []Value, index int) (string, error) {
+	s, ok := args[index].(String)
+	if !ok {
+		return "", fmt.Errorf("%s() expects a string as argument %d but got %s.", fn, index+1, args[index].Kind())
+	}
+	return string(s), nil
+}
+
+func numberArg(fn string, args []Value, index int) (float64, error) {
+	n, ok := args[index].(Number)
+	if !ok {
+		return 0, fmt.Errorf("%s() expects a number as argument %d but got %s.", fn, index+1, args[index].Kind())
+	}
+	return float64(n), nil
+}
+
+func intArg(fn string, args []Value, index int) (int, error) {
+	n, err := numberArg(fn, args, index)
+	if err != nil {
+		return 0, err
+	}
+	if n != math.Trunc(n) {
+		return 0, fmt.Errorf("%s() expects an integer as argument %d but got %s.", fn, index+1, strconv.FormatFloat(n, 'f', -1, 64))
+	}
+	return int(n), nil
+}
@@ -0,0 +1,822 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type Parser struct {
+	tokens      []Token
+	current     int
+	diagnostics *Diagnostics
+	errors      []error
+
+	// kind of function ("function", "method", "initializer") and class
+	// ("class", "subclass") being parsed, used to reject misplaced
+	// return, this and super
+	function string
+	class    string
+
+	// how many loops enclose the current statement, reset inside functions
+	loopDepth int
+
+	// how many blocks enclose the current statement, synchronize leaves
+	// their closing braces alone
+	blockDepth int
+
+	// set for REPL input, where the last expression statement may leave off
+	// its semicolon
+	repl bool
+}
+
+func NewParser(tokens []Token, diagnostics *Diagnostics) *Parser {
+	return &Parser{tokens: tokens, current: 0, diagnostics: diagnostics, errors: make([]error, 0)}
+}
+
+func (p *Parser) Parse() ([]Stmt, error) {
+	statements := make([]Stmt, 0)
+
+	for !p.isAtEnd() {
+		statement, err := p.declaration()
+		if err != nil {
+			// already reported and recovered from
+			continue
+		}
+		statements = append(statements, statement)
+	}
+
+	return statements, errors.Join(p.errors...)
+}
+
+// declaration is where the parser recovers from syntax errors: on an error
+// it synchronizes to the start of the next declaration and returns the
+// error with no statement
+func (p *Parser) declaration() (Stmt, error) {
+	start := p.current
+
+	var stmt Stmt
+	var err error
+	switch {
+	case p.match(CLASS):
+		stmt, err = p.classDeclaration()
+	case p.match(FUN):
+		stmt, err = p.funDeclaration("function")
+	case p.match(VAR):
+		stmt, err = p.varDeclaration()
+	default:
+		stmt, err = p.statement()
+	}
+
+	if err != nil {
+		p.synchronize(start)
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (p *Parser) classDeclaration() (Stmt, error) {
+	name, err := p.consume(IDENTIFIER, "Expect class name.")
+	if err != nil {
+		return nil, err
+	}
+
+	enclosingClass := p.class
+	p.class = "class"
+	defer func() { p.class = enclosingClass }()
+
+	var superclass *VariableExpr = nil
+	if p.match(LESS) {
+		superName, err := p.consume(IDENTIFIER, "Expect superclass name.")
+		if err != nil {
+			return nil, err
+		}
+		if superName.Lexeme == name.Lexeme {
+			p.parseError(superName, "A class can't inherit from itself.")
+		}
+		superclass = NewVariableExpr(superName)
+		p.class = "subclass"
+	}
+
+	_, err = p.consume(LEFT_BRACE, "Expect '{' before class body.")
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]FunctionStmt, 0)
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		method, err := p.funDeclaration("method")
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method.(FunctionStmt))
+	}
+
+	_, err = p.consume(RIGHT_BRACE, "Expect '}' after class body.")
+	if err != nil {
+		return nil, err
+	}
+	return NewClassStmt(name, superclass, methods), nil
+}
+
+func (p *Parser) funDeclaration(kind string) (Stmt, error) {
+	name, err := p.consume(IDENTIFIER, fmt.Sprintf("Expect %s name.", kind))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(LEFT_PAREN, "Expect '(' after function name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var params []Token
+	if !p.check(RIGHT_PAREN) {
+		param, err := p.consume(IDENTIFIER, "Expect parameter name.")
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+		for p.match(COMMA) {
+			if len(params) >= 255 {
+				p.parseError(params[len(params)-1], "Can't have more than 255 parameters.")
+			}
+			param, err := p.consume(IDENTIFIER, "Expect parameter name.")
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, param)
+		}
+	}
+
+	_, err = p.consume(RIGHT_PAREN, "Expect ')' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(LEFT_BRACE, fmt.Sprintf("Expect '{' before %s body.", kind))
+	if err != nil {
+		return nil, err
+	}
+
+	enclosingFunction, enclosingLoopDepth := p.function, p.loopDepth
+	p.function = kind
+	if kind == "method" && name.Lexeme == "init" {
+		p.function = "initializer"
+	}
+	p.loopDepth = 0
+	stmt, err := p.blockStatement()
+	p.function, p.loopDepth = enclosingFunction, enclosingLoopDepth
+	if err != nil {
+		return nil, err
+	}
+	block := stmt.(BlockStmt)
+
+	return NewFunctionStmt(name, params, block.Statements), nil
+}
+
+func (p *Parser) varDeclaration() (Stmt, error) {
+	name, err := p.consume(IDENTIFIER, "Expect variable name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var initializer Expr = nil
+	if p.match(EQUAL) {
+		initializer, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.consume(SEMICOLON, "Expect ';' after variable declaration.")
+	if err != nil {
+		return nil, err
+	}
+	return NewVariableStmt(name, initializer), nil
+}
+
+func (p *Parser) statement() (Stmt, error) {
+	// Check non-expression statements first and leave as a fallthrough
+	// "Hard to proactively recognize an expression from its first token"
+	if p.match(FOR) {
+		return p.forStatement()
+	}
+	if p.match(IF) {
+		return p.ifStatement()
+	}
+	if p.match(PRINT) {
+		return p.printStatement()
+	}
+	if p.match(RETURN) {
+		return p.returnStatement()
+	}
+	if p.match(BREAK, CONTINUE) {
+		return p.loopControlStatement()
+	}
+	if p.match(WHILE) {
+		return p.whileStatement()
+	}
+	if p.match(LEFT_BRACE) {
+		return p.blockStatement()
+	}
+	return p.expressionStatement()
+}
+
+func (p *Parser) expressionStatement() (Stmt, error) {
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if p.repl && p.isAtEnd() {
+		return NewExpressionStmt(expr), nil
+	}
+	_, err = p.consume(SEMICOLON, "Expect ';' after value.")
+	if err != nil {
+		return nil, err
+	}
+	return NewExpressionStmt(expr), nil
+}
+
+// forStatement desugars `for (init; cond; incr) body` into
+// `{ init; while (cond) body }` with incr kept on the WhileStmt
+func (p *Parser) forStatement() (Stmt, error) {
+	_, err := p.consume(LEFT_PAREN, "Expect '(' after 'for'.")
+	if err != nil {
+		return nil, err
+	}
+
+	var initializer Stmt = nil
+	if p.match(SEMICOLON) {
+		// no initializer
+	} else if p.match(VAR) {
+		initializer, err = p.varDeclaration()
+	} else {
+		initializer, err = p.expressionStatement()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var condition Expr = nil
+	if !p.check(SEMICOLON) {
+		condition, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = p.consume(SEMICOLON, "Expect ';' after loop condition.")
+	if err != nil {
+		return nil, err
+	}
+
+	var increment Expr = nil
+	if !p.check(RIGHT_PAREN) {
+		increment, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = p.consume(RIGHT_PAREN, "Expect ')' after for clauses.")
+	if err != nil {
+		return nil, err
+	}
+
+	p.loopDepth++
+	body, err := p.statement()
+	p.loopDepth--
+	if err != nil {
+		return nil, err
+	}
+
+	if condition == nil {
+		condition = NewLiteralExpr(true)
+	}
+	body = NewWhileStmt(condition, body, increment)
+	if initializer != nil {
+		body = NewBlockStmt([]Stmt{initializer, body})
+	}
+
+	return body, nil
+}
+
+func (p *Parser) ifStatement() (Stmt, error) {
+	_, err := p.consume(LEFT_PAREN, "Expect '(' after 'if'.")
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(RIGHT_PAREN, "Expect ')' after if condition.")
+	if err != nil {
+		return nil, err
+	}
+
+	thenBranch, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	var elseBranch Stmt = nil
+	if p.match(ELSE) {
+		elseBranch, err = p.statement()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewIfStmt(expr, thenBranch, elseBranch), nil
+}
+
+func (p *Parser) printStatement() (Stmt, error) {
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(SEMICOLON, "Expect ';' after value.")
+	if err != nil {
+		return nil, err
+	}
+	return NewPrintStmt(expr), nil
+}
+
+func (p *Parser) returnStatement() (Stmt, error) {
+	keyword := p.previous()
+	if p.function == "" {
+		// report but keep parsing, the statement itself is well formed
+		p.parseError(keyword, "Can't return from top-level code.")
+	}
+
+	var value Expr = nil
+	var err error
+	if !p.check(SEMICOLON) {
+		if p.function == "initializer" {
+			p.parseError(keyword, "Can't return a value from an initializer.")
+		}
+		value, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.consume(SEMICOLON, "Expect ';' after return value.")
+	if err != nil {
+		return nil, err
+	}
+	return NewReturnStmt(keyword, value), nil
+}
+
+func (p *Parser) whileStatement() (Stmt, error) {
+	_, err := p.consume(LEFT_PAREN, "Expect '(' after 'while'.")
+	if err != nil {
+		return nil, err
+	}
+	condition, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(RIGHT_PAREN, "Expect ')' after condition.")
+	if err != nil {
+		return nil, err
+	}
+	p.loopDepth++
+	body, err := p.statement()
+	p.loopDepth--
+	if err != nil {
+		return nil, err
+	}
+	return NewWhileStmt(condition, body, nil), nil
+}
+
+func (p *Parser) loopControlStatement() (Stmt, error) {
+	keyword := p.previous()
+	if p.loopDepth == 0 {
+		p.parseError(keyword, fmt.Sprintf("Can't use '%s' outside of a loop.", keyword.Lexeme))
+	}
+
+	_, err := p.consume(SEMICOLON, fmt.Sprintf("Expect ';' after '%s'.", keyword.Lexeme))
+	if err != nil {
+		return nil, err
+	}
+
+	if keyword.Type == BREAK {
+		return NewBreakStmt(keyword), nil
+	}
+	return NewContinueStmt(keyword), nil
+}
+
+func (p *Parser) blockStatement() (Stmt, error) {
+	stmts := make([]Stmt, 0)
+	p.blockDepth++
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		stmt, err := p.declaration()
+		if err != nil {
+			// already reported, the rest of the block is still checked
+			continue
+		}
+		stmts = append(stmts, stmt)
+	}
+	p.blockDepth--
+	_, err := p.consume(RIGHT_BRACE, "Expected closing brace '}'.")
+	return NewBlockStmt(stmts), err
+}
+
+func (p *Parser) expression() (Expr, error) {
+	return p.assignment()
+}
+
+func (p *Parser) assignment() (Expr, error) {
+	expr, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(EQUAL) {
+		equals := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+
+		switch expr := expr.(type) {
+		case *VariableExpr:
+			return NewAssignmentExpr(expr.Name, value), nil
+		case *GetExpr:
+			return NewSetExpr(expr.Object, expr.Name, value), nil
+		}
+
+		p.parseError(equals, "Invalid assignment target.", "did you mean `==`?")
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) conditional() (Expr, error) {
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(QUESTION) {
+		then, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(COLON, "Expect ':' after then branch of conditional expression.")
+		if err != nil {
+			return nil, err
+		}
+		// recursing on the else branch makes ?: right associative
+		otherwise, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+		expr = NewConditionalExpr(expr, then, otherwise)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) or() (Expr, error) {
+	expr, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(OR) {
+		op := p.previous()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		expr = NewLogicalExpr(op, expr, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) and() (Expr, error) {
+	expr, err := p.equality()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(AND) {
+		op := p.previous()
+		right, err := p.equality()
+		if err != nil {
+			return nil, err
+		}
+		expr = NewLogicalExpr(op, expr, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) equality() (Expr, error) {
+	expr, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(BANG_EQUAL, EQUAL_EQUAL) {
+		op := p.previous()
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		expr = NewBinaryExpr(op, expr, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) comparison() (Expr, error) {
+	expr, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(GREATER, GREATER_EQUAL, LESS, LESS_EQUAL) {
+		op := p.previous()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		expr = NewBinaryExpr(op, expr, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) term() (Expr, error) {
+	expr, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(MINUS, PLUS) {
+		op := p.previous()
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		expr = NewBinaryExpr(op, expr, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) factor() (Expr, error) {
+	expr, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(SLASH, STAR) {
+		op := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		expr = NewBinaryExpr(op, expr, right)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) unary() (Expr, error) {
+	if p.match(BANG, MINUS) {
+		op := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return NewUnaryExpr(op, right), nil
+	}
+
+	return p.call()
+}
+
+func (p *Parser) call() (Expr, error) {
+	expr, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for true {
+		if p.match(LEFT_PAREN) {
+			expr, err = p.finishCall(expr)
+			if err != nil {
+				return nil, err
+			}
+		} else if p.match(DOT) {
+			name, err := p.consume(IDENTIFIER, "Expect property name after '.'.")
+			if err != nil {
+				return nil, err
+			}
+			expr = NewGetExpr(expr, name)
+		} else {
+			break
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) primary() (Expr, error) {
+	if p.match(FALSE) {
+		return NewLiteralExpr(false), nil
+	}
+	if p.match(TRUE) {
+		return NewLiteralExpr(true), nil
+	}
+	if p.match(NIL) {
+		return NewLiteralExpr(nil), nil
+	}
+	if p.match(NUMBER, STRING) {
+		return NewLiteralExpr(p.previous().Literal), nil
+	}
+
+	if p.match(THIS) {
+		keyword := p.previous()
+		if p.class == "" {
+			p.parseError(keyword, "Can't use 'this' outside of a class.")
+		}
+		return NewThisExpr(keyword), nil
+	}
+
+	if p.match(SUPER) {
+		keyword := p.previous()
+		if p.class == "" {
+			p.parseError(keyword, "Can't use 'super' outside of a class.")
+		} else if p.class != "subclass" {
+			p.parseError(keyword, "Can't use 'super' in a class with no superclass.")
+		}
+		_, err := p.consume(DOT, "Expect '.' after 'super'.")
+		if err != nil {
+			return nil, err
+		}
+		method, err := p.consume(IDENTIFIER, "Expect superclass method name.")
+		if err != nil {
+			return nil, err
+		}
+		return NewSuperExpr(keyword, method), nil
+	}
+
+	if p.match(IDENTIFIER) {
+		return NewVariableExpr(p.previous()), nil
+	}
+
+	if p.match(LEFT_PAREN) {
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(RIGHT_PAREN, "Expect ')' after expression.")
+		if err != nil {
+			return nil, err
+		}
+		return NewGroupingExpr(expr), nil
+	}
+
+	return nil, p.parseError(p.peek(), "Expect expression.")
+}
+
+func (p *Parser) match(types ...TokenType) bool {
+	if slices.ContainsFunc(types, p.check) {
+		p.advance()
+		return true
+	}
+	return false
+}
+
+func (p *Parser) consume(t TokenType, msg string) (Token, error) {
+	if p.check(t) {
+		return p.advance(), nil
+	}
+
+	return Token{}, p.parseError(p.peek(), msg)
+}
+
+func (p *Parser) check(t TokenType) bool {
+	if p.isAtEnd() {
+		return false
+	}
+	return p.peek().Type == t
+}
+
+func (p *Parser) advance() Token {
+	if !p.isAtEnd() {
+		p.current++
+	}
+	return p.previous()
+}
+
+func (p *Parser) isAtEnd() bool {
+	return p.peek().Type == EOF
+}
+
+func (p *Parser) peek() Token {
+	return p.tokens[p.current]
+}
+
+func (p *Parser) previous() Token {
+	return p.tokens[p.current-1]
+}
+
+func (p *Parser) parseError(tok Token, msg string, notes ...string) ParserError {
+	// EOF sits after any trailing newlines, possibly on a line of its own,
+	// so errors there point just past the last real token instead
+	if tok.Type == EOF && len(p.tokens) > 1 {
+		last := p.tokens[len(p.tokens)-2]
+		tok.Line, tok.Column = last.EndLine, last.EndColumn
+		tok.EndLine, tok.EndColumn = last.EndLine, last.EndColumn
+		tok.Offset = last.Offset + len(last.Lexeme)
+	}
+	err := NewParserError(tok, msg, notes...)
+	// a second error at the same token is a cascade from the first, such as
+	// every unclosed block failing at EOF
+	if len(p.errors) > 0 {
+		var last ParserError
+		if errors.As(p.errors[len(p.errors)-1], &last) && last.Token.Offset == tok.Offset {
+			return err
+		}
+	}
+	p.diagnostics.Add(err)
+	p.errors = append(p.errors, err)
+	return err
+}
+
+// synchronize discards the rest of a declaration that failed to parse, so
+// one mistake is reported once. It stops after a `;` or a closing brace, or
+// before a keyword that starts a statement. Braces opened since start are
+// skipped through to their close, and the `}` of an enclosing block is left
+// for the block to consume.
+func (p *Parser) synchronize(start int) {
+	depth := 0
+	for _, token := range p.tokens[start:p.current] {
+		switch token.Type {
+		case LEFT_BRACE:
+			depth++
+		case RIGHT_BRACE:
+			depth = max(depth-1, 0)
+		}
+	}
+
+	for !p.isAtEnd() {
+		// always move past at least one token
+		if p.current > start && depth == 0 {
+			switch p.previous().Type {
+			case SEMICOLON, RIGHT_BRACE:
+				return
+			}
+			switch p.peek().Type {
+			case CLASS, FUN, VAR, FOR, IF, WHILE, PRINT, RETURN, BREAK, CONTINUE:
+				return
+			case RIGHT_BRACE:
+				if p.blockDepth > 0 {
+					return
+				}
+			}
+		}
+
+		switch p.advance().Type {
+		case LEFT_BRACE:
+			depth++
+		case RIGHT_BRACE:
+			depth = max(depth-1, 0)
+		}
+	}
+}
+
+func (p *Parser) finishCall(expr Expr) (Expr, error) {
+	arguments := make([]Expr, 0)
+	if !p.check(RIGHT_PAREN) {
+		if len(arguments) >= 255 {
+			p.parseError(p.peek(), "Can't have more than 255 arguments.")
+		}
+
+		arg, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, arg)
+		for p.match(COMMA) {
+			arg, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, arg)
+		}
+	}
+
+	paren, err := p.consume(RIGHT_PAREN, "Expect ')' after arguments.")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCallExpr(expr, paren, arguments), nil
+}
@@ -0,0 +1,91 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// EvalLine evaluates source typed at a prompt, where the final expression
+// statement may leave off its semicolon. It returns that expression's value,
+// or false when source ends with any other statement.
+func (r *Runtime) EvalLine(source string) (Value, bool, error) {
+	statements, value, err := r.eval("<repl>", source, true)
+	if err != nil || len(statements) == 0 {
+		return nil, false, err
+	}
+	if _, ok := statements[len(statements)-1].(ExpressionStmt); !ok {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+// ParseLine is Parse for source typed at a prompt, see EvalLine
+func (r *Runtime) ParseLine(source string) ([]Stmt, error) {
+	return r.parse("<repl>", source, true)
+}
+
+// Globals returns a copy of the global variables, natives included
+func (r *Runtime) Globals() map[string]Value {
+	return maps.Clone(r.interpreter.Globals.values)
+}
+
+// Define sets a global variable, value is converted as for RegisterNative
+func (r *Runtime) Define(name string, value any) error {
+	v, ok := valueOf(value)
+	if !ok {
+		return fmt.Errorf("%s is a %T, which Lox has no value for", name, value)
+	}
+	r.interpreter.Globals.define(name, v)
+	return nil
+}
+
+// Incomplete reports whether source looks like the start of a longer
+// program: a string or bracket is still open, or the parser ran out of
+// tokens. A prompt uses it to keep reading lines. Nothing is reported.
+func (r *Runtime) Incomplete(source string) bool {
+	// a private sink, so nothing is reported
+	diagnostics := NewDiagnostics()
+	tokens, lexErrors := NewLexer(source, diagnostics).ScanTokens()
+	for _, err := range lexErrors {
+		// an unterminated string is the only error spanning from a quote to
+		// the end of the source
+		var lexErr LexerError
+		if !errors.As(err, &lexErr) {
+			continue
+		}
+		span := lexErr.Span()
+		if span.Offset < len(source) && source[span.Offset] == '"' && span.Offset+span.Length >= len(source) {
+			return true
+		}
+	}
+	if len(lexErrors) > 0 {
+		return false
+	}
+
+	depth := 0
+	for _, token := range tokens {
+		switch token.Type {
+		case LEFT_PAREN, LEFT_BRACE:
+			depth++
+		case RIGHT_PAREN, RIGHT_BRACE:
+			depth--
+		}
+	}
+	if depth != 0 {
+		return depth > 0
+	}
+
+	parser := NewParser(tokens, diagnostics)
+	parser.repl = true
+	_, err := parser.Parse()
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			var parseErr ParserError
+			if errors.As(err, &parseErr) && parseErr.Token.Type == EOF {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+package lox
+
+import "errors"
+
+// Resolver walks the tree once before it runs and tells the interpreter how
+// many scopes away each local variable is declared. Globals are left
+// unresolved and looked up dynamically.
+type Resolver struct {
+	interpreter *Interpreter
+	diagnostics *Diagnostics
+	errors      []error
+
+	// each scope maps a name to whether its initializer has finished
+	scopes []map[string]bool
+}
+
+func NewResolver(interpreter *Interpreter, diagnostics *Diagnostics) *Resolver {
+	return &Resolver{interpreter: interpreter, diagnostics: diagnostics, errors: make([]error, 0), scopes: make([]map[string]bool, 0)}
+}
+
+// Resolve returns every error reported while resolving statements
+func (r *Resolver) Resolve(statements []Stmt) error {
+	err := r.resolveStmts(statements)
+	if err != nil {
+		return err
+	}
+	return errors.Join(r.errors...)
+}
+
+func (r *Resolver) VisitClassStmt(stmt ClassStmt) error {
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+
+	if stmt.Superclass != nil {
+		_, err := r.resolveExpr(stmt.Superclass)
+		if err != nil {
+			return err
+		}
+		r.beginScope()
+		defer r.endScope()
+		r.scopes[len(r.scopes)-1]["super"] = true
+	}
+
+	r.beginScope()
+	defer r.endScope()
+	r.scopes[len(r.scopes)-1]["this"] = true
+
+	for _, method := range stmt.Methods {
+		err := r.resolveFunction(method)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) VisitFunctionStmt(stmt FunctionStmt) error {
+	// define eagerly so the function can refer to itself recursively
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+	return r.resolveFunction(stmt)
+}
+
+func (r *Resolver) VisitVariableStmt(stmt VariableStmt) error {
+	r.declare(stmt.Name)
+	if stmt.Initializer != nil {
+		_, err := r.resolveExpr(stmt.Initializer)
+		if err != nil {
+			return err
+		}
+	}
+	r.define(stmt.Name)
+	return nil
+}
+
+func (r *Resolver) VisitExpressionStmt(stmt ExpressionStmt) error {
+	_, err := r.resolveExpr(stmt.Expr)
+	return err
+}
+
+func (r *Resolver) VisitPrintStmt(stmt PrintStmt) error {
+	_, err := r.resolveExpr(stmt.Expr)
+	return err
+}
+
+func (r *Resolver) VisitIfStmt(stmt IfStmt) error {
+	_, err := r.resolveExpr(stmt.Guard)
+	if err != nil {
+		return err
+	}
+	err = r.resolveStmt(stmt.ThenBranch)
+	if err != nil {
+		return err
+	}
+	if stmt.ElseBranch != nil {
+		return r.resolveStmt(stmt.ElseBranch)
+	}
+	return nil
+}
+
+func (r *Resolver) VisitWhileStmt(stmt WhileStmt) error {
+	_, err := r.resolveExpr(stmt.Condition)
+	if err != nil {
+		return err
+	}
+	err = r.resolveStmt(stmt.Body)
+	if err != nil {
+		return err
+	}
+	if stmt.Increment != nil {
+		_, err = r.resolveExpr(stmt.Increment)
+	}
+	return err
+}
+
+func (r *Resolver) VisitBlockStmt(stmt BlockStmt) error {
+	r.beginScope()
+	defer r.endScope()
+	return r.resolveStmts(stmt.Statements)
+}
+
+func (r *Resolver) VisitReturnStmt(stmt ReturnStmt) error {
+	if stmt.Value != nil {
+		_, err := r.resolveExpr(stmt.Value)
+		return err
+	}
+	return nil
+}
+
+func (r *Resolver) VisitBreakStmt(stmt BreakStmt) error {
+	return nil
+}
+
+func (r *Resolver) VisitContinueStmt(stmt ContinueStmt) error {
+	return nil
+}
+
+func (r *Resolver) VisitAssignmentExpr(expr *AssignmentExpr) (any, error) {
+	_, err := r.resolveExpr(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+	r.resolveLocal(expr, expr.Name)
+	return nil, nil
+}
+
+func (r *Resolver) VisitConditionalExpr(expr *ConditionalExpr) (any, error) {
+	return r.resolveExprs(expr.Condition, expr.Then, expr.Otherwise)
+}
+
+func (r *Resolver) VisitLogicalExpr(expr *LogicalExpr) (any, error) {
+	return r.resolveExprs(expr.Left, expr.Right)
+}
+
+func (r *Resolver) VisitBinaryExpr(expr *BinaryExpr) (any, error) {
+	return r.resolveExprs(expr.Left, expr.Right)
+}
+
+func (r *Resolver) VisitGroupingExpr(expr *GroupingExpr) (any, error) {
+	return r.resolveExpr(expr.Expr)
+}
+
+func (r *Resolver) VisitLiteralExpr(expr *LiteralExpr) (any, error) {
+	return nil, nil
+}
+
+func (r *Resolver) VisitUnaryExpr(expr *UnaryExpr) (any, error) {
+	return r.resolveExpr(expr.Expr)
+}
+
+func (r *Resolver) VisitCallExpr(expr *CallExpr) (any, error) {
+	_, err := r.resolveExpr(expr.Callee)
+	if err != nil {
+		return nil, err
+	}
+	return r.resolveExprs(expr.Args...)
+}
+
+func (r *Resolver) VisitGetExpr(expr *GetExpr) (any, error) {
+	// properties are looked up dynamically, only the object is resolved
+	return r.resolveExpr(expr.Object)
+}
+
+func (r *Resolver) VisitSetExpr(expr *SetExpr) (any, error) {
+	return r.resolveExprs(expr.Value, expr.Object)
+}
+
+func (r *Resolver) VisitSuperExpr(expr *SuperExpr) (any, error) {
+	r.resolveLocal(expr, expr.Keyword)
+	return nil, nil
+}
+
+func (r *Resolver) VisitThisExpr(expr *ThisExpr) (any, error) {
+	r.resolveLocal(expr, expr.Keyword)
+	return nil, nil
+}
+
+func (r *Resolver) VisitVariableExpr(expr *VariableExpr) (any, error) {
+	if len(r.scopes) > 0 {
+		defined, declared := r.scopes[len(r.scopes)-1][expr.Name.Lexeme]
+		if declared && !defined {
+			r.resolveError(expr.Name, "Can't read local variable in its own initializer.")
+		}
+	}
+
+	r.resolveLocal(expr, expr.Name)
+	return nil, nil
+}
+
+func (r *Resolver) resolveFunction(function FunctionStmt) error {
+	r.beginScope()
+	defer r.endScope()
+
+	for _, param := range function.Params {
+		r.declare(param)
+		r.define(param)
+	}
+	return r.resolveStmts(function.Body)
+}
+
+// resolveLocal records the distance to the innermost scope declaring name
+func (r *Resolver) resolveLocal(expr Expr, name Token) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if _, ok := r.scopes[i][name.Lexeme]; ok {
+			r.interpreter.resolve(expr, len(r.scopes)-1-i)
+			return
+		}
+	}
+}
+
+func (r *Resolver) declare(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+
+	scope := r.scopes[len(r.scopes)-1]
+	if _, ok := scope[name.Lexeme]; ok {
+		r.resolveError(name, "Already a variable with this name in this scope.")
+	}
+	scope[name.Lexeme] = false
+}
+
+func (r *Resolver) define(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+	r.scopes[len(r.scopes)-1][name.Lexeme] = true
+}
+
+func (r *Resolver) beginScope() {
+	r.scopes = append(r.scopes, make(map[string]bool))
+}
+
+func (r *Resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *Resolver) resolveStmts(statements []Stmt) error {
+	for _, stmt := range statements {
+		err := r.resolveStmt(stmt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) resolveStmt(stmt Stmt) error {
+	return stmt.Accept(r)
+}
+
+func (r *Resolver) resolveExpr(expr Expr) (any, error) {
+	return expr.Accept(r)
+}
+
+func (r *Resolver) resolveExprs(exprs ...Expr) (any, error) {
+	for _, expr := range exprs {
+		_, err := r.resolveExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) resolveError(tok Token, msg string) ResolverError {
+	err := NewResolverError(tok, msg)
+	r.diagnostics.Add(err)
+	r.errors = append(r.errors, err)
+	return err
+}
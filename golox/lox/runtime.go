@@ -0,0 +1,252 @@
+package lox
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Runtime is the entry point for embedding Lox. Each Runtime has its own
+// globals and output writers, so several can be used side by side.
+//
+// Every phase adds the errors it finds to diagnostics, and the runtime
+// renders them through reporter once the phase is done.
+type Runtime struct {
+	interpreter *Interpreter
+	diagnostics *Diagnostics
+	reporter    *ErrorReporter
+
+	// read by the input native, shared with whatever else reads the same
+	// input so neither buffers away lines meant for the other
+	stdin *bufio.Reader
+
+	// globals the embedder defined, kept when the interpreter is reset
+	args    []string
+	natives []*NativeFn
+}
+
+func NewRuntime() *Runtime {
+	r := &Runtime{diagnostics: NewDiagnostics(), reporter: NewErrorReporter(os.Stderr), stdin: bufio.NewReader(os.Stdin)}
+	r.Reset()
+	return r
+}
+
+// Reset starts over with a new interpreter. The output writers, stdin, args
+// and registered natives carry over, everything scripts defined is dropped.
+func (r *Runtime) Reset() {
+	interpreter := NewInterpreter()
+	interpreter.diagnostics = r.diagnostics
+	interpreter.stdin = r.stdin
+	if r.interpreter != nil {
+		interpreter.out = r.interpreter.out
+	}
+	r.interpreter = interpreter
+
+	if r.args != nil {
+		r.defineArgs()
+	}
+	for _, native := range r.natives {
+		r.interpreter.Globals.define(native.name, native)
+	}
+}
+
+// report renders the errors collected so far and clears them
+func (r *Runtime) report() {
+	for _, err := range r.diagnostics.Errors() {
+		r.reporter.Report(err)
+	}
+	r.diagnostics.Clear()
+}
+
+// SetStdout sets where print statements write to
+func (r *Runtime) SetStdout(w io.Writer) {
+	r.interpreter.out = w
+}
+
+// SetStdin sets where the input native reads from. Pass a *bufio.Reader to
+// share it with other code reading the same input, eg. a REPL reading lines,
+// other readers are wrapped in a new one.
+func (r *Runtime) SetStdin(in io.Reader) {
+	reader, ok := in.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(in)
+	}
+	r.stdin = reader
+	r.interpreter.stdin = reader
+}
+
+// SetStderr sets where errors are reported to
+func (r *Runtime) SetStderr(w io.Writer) {
+	r.reporter.SetOutput(w)
+}
+
+// SetDiagnostics sets how errors are written, DiagnosticsText or DiagnosticsJSON
+func (r *Runtime) SetDiagnostics(format string) error {
+	return r.reporter.SetFormat(format)
+}
+
+// Eval runs source against the runtime's globals, so definitions carry over
+// between calls. It returns the value of the final statement if that is an
+// expression statement. Lexer, parser and resolver errors stop evaluation
+// before anything runs and are all returned joined together.
+//
+// Values come back as Go types: nil, bool, float64 and string, or the Lox
+// function, class, instance or list itself.
+func (r *Runtime) Eval(source string) (any, error) {
+	return r.EvalFile("<script>", source)
+}
+
+// EvalFile is Eval with the file name used when reporting errors
+func (r *Runtime) EvalFile(name, source string) (any, error) {
+	_, value, err := r.eval(name, source, false)
+	if err != nil {
+		return nil, err
+	}
+	return goValue(value), nil
+}
+
+func (r *Runtime) eval(name, source string, repl bool) ([]Stmt, Value, error) {
+	statements, err := r.parse(name, source, repl)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := r.run(statements)
+	if err != nil {
+		return nil, nil, err
+	}
+	return statements, value, nil
+}
+
+// run resolves and interprets parsed statements
+func (r *Runtime) run(statements []Stmt) (Value, error) {
+	resolver := NewResolver(r.interpreter, r.diagnostics)
+	err := resolver.Resolve(statements)
+	r.report()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := r.interpreter.Interpret(statements)
+	r.report()
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Tokens scans source without parsing it
+func (r *Runtime) Tokens(name, source string) ([]Token, error) {
+	lexer := NewLexer(source, r.diagnostics)
+	lexer.file.Name = name
+	tokens, lexErrors := lexer.ScanTokens()
+	r.report()
+	return tokens, errors.Join(lexErrors...)
+}
+
+// Parse scans and parses source without resolving or running it
+func (r *Runtime) Parse(name, source string) ([]Stmt, error) {
+	return r.parse(name, source, false)
+}
+
+func (r *Runtime) parse(name, source string, repl bool) ([]Stmt, error) {
+	tokens, err := r.Tokens(name, source)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := NewParser(tokens, r.diagnostics)
+	parser.repl = repl
+	statements, err := parser.Parse()
+	r.report()
+	return statements, err
+}
+
+// Format returns source in the canonical style, comments included
+func (r *Runtime) Format(name, source string) (string, error) {
+	tokens, err := r.Tokens(name, source)
+	if err != nil {
+		return "", err
+	}
+
+	parser := NewParser(tokens, r.diagnostics)
+	statements, err := parser.Parse()
+	r.report()
+	if err != nil {
+		return "", err
+	}
+	return NewFormatter(tokens).Format(statements)
+}
+
+// Check reports every error that can be found without running source. It
+// leaves the runtime's globals untouched.
+func (r *Runtime) Check(name, source string) error {
+	statements, err := r.Parse(name, source)
+	if err != nil {
+		return err
+	}
+
+	resolver := NewResolver(NewInterpreter(), r.diagnostics)
+	err = resolver.Resolve(statements)
+	r.report()
+	return err
+}
+
+// Completions returns the keywords and global names starting with prefix,
+// sorted, for completion at the REPL
+func (r *Runtime) Completions(prefix string) []string {
+	var names []string
+	for keyword := range keywords {
+		if strings.HasPrefix(keyword, prefix) {
+			names = append(names, keyword)
+		}
+	}
+	for name := range r.interpreter.Globals.values {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// SetArgs exposes command line arguments to scripts as the `args` list
+func (r *Runtime) SetArgs(args []string) {
+	r.args = append([]string{}, args...)
+	r.defineArgs()
+}
+
+func (r *Runtime) defineArgs() {
+	elements := make([]Value, len(r.args))
+	for i, arg := range r.args {
+		elements[i] = String(arg)
+	}
+	r.interpreter.Globals.define("args", NewLoxList(elements))
+}
+
+// RegisterNative defines a global function implemented in Go. Arguments
+// and the result use the same Go types as Eval, an int result is also
+// accepted as a number. Errors returned by fn surface as runtime errors at
+// the call site.
+func (r *Runtime) RegisterNative(name string, arity int, fn func(args []any) (any, error)) {
+	native := NewNativeFn(name, arity, func(interpreter *Interpreter, args []Value) (Value, error) {
+		goArgs := make([]any, len(args))
+		for i, arg := range args {
+			goArgs[i] = goValue(arg)
+		}
+		result, err := fn(goArgs)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := valueOf(result)
+		if !ok {
+			return nil, fmt.Errorf("%s() returned a %T, which Lox has no value for.", name, result)
+		}
+		return value, nil
+	})
+	r.natives = append(r.natives, native)
+	r.interpreter.Globals.define(name, native)
+}
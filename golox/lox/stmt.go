@@ -1,9 +1,15 @@
-package main
+package lox
 
 type Stmt interface {
 	Accept(v StmtVisitor) error
 }
 
+type ClassStmt struct {
+	Name       Token
+	Superclass *VariableExpr
+	Methods    []FunctionStmt
+}
+
 type FunctionStmt struct {
 	Name   Token
 	Params []Token
@@ -29,15 +35,34 @@ type PrintStmt struct {
 	Expr Expr
 }
 
+// Increment is only set by desugared for loops so that continue still runs it
 type WhileStmt struct {
 	Condition Expr
 	Body      Stmt
+	Increment Expr
 }
 
 type BlockStmt struct {
 	Statements []Stmt
 }
 
+type ReturnStmt struct {
+	Keyword Token
+	Value   Expr
+}
+
+type BreakStmt struct {
+	Keyword Token
+}
+
+type ContinueStmt struct {
+	Keyword Token
+}
+
+func NewClassStmt(name Token, superclass *VariableExpr, methods []FunctionStmt) ClassStmt {
+	return ClassStmt{Name: name, Superclass: superclass, Methods: methods}
+}
+
 func NewFunctionStmt(name Token, params []Token, body []Stmt) FunctionStmt {
 	return FunctionStmt{name, params, body}
 }
@@ -58,14 +83,30 @@ func NewPrintStmt(expr Expr) PrintStmt {
 	return PrintStmt{Expr: expr}
 }
 
-func NewWhileStmt(condition Expr, body Stmt) WhileStmt {
-	return WhileStmt{Condition: condition, Body: body}
+func NewWhileStmt(condition Expr, body Stmt, increment Expr) WhileStmt {
+	return WhileStmt{Condition: condition, Body: body, Increment: increment}
 }
 
 func NewBlockStmt(statements []Stmt) BlockStmt {
 	return BlockStmt{Statements: statements}
 }
 
+func NewReturnStmt(keyword Token, value Expr) ReturnStmt {
+	return ReturnStmt{Keyword: keyword, Value: value}
+}
+
+func (s ClassStmt) Accept(v StmtVisitor) error {
+	return v.VisitClassStmt(s)
+}
+
+func NewBreakStmt(keyword Token) BreakStmt {
+	return BreakStmt{Keyword: keyword}
+}
+
+func NewContinueStmt(keyword Token) ContinueStmt {
+	return ContinueStmt{Keyword: keyword}
+}
+
 func (s FunctionStmt) Accept(v StmtVisitor) error {
 	return v.VisitFunctionStmt(s)
 }
@@ -93,3 +134,15 @@ func (s WhileStmt) Accept(v StmtVisitor) error {
 func (s BlockStmt) Accept(v StmtVisitor) error {
 	return v.VisitBlockStmt(s)
 }
+
+func (s ReturnStmt) Accept(v StmtVisitor) error {
+	return v.VisitReturnStmt(s)
+}
+
+func (s BreakStmt) Accept(v StmtVisitor) error {
+	return v.VisitBreakStmt(s)
+}
+
+func (s ContinueStmt) Accept(v StmtVisitor) error {
+	return v.VisitContinueStmt(s)
+}
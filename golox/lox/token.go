@@ -1,18 +1,48 @@
-package main
+package lox
 
+import "fmt"
+
+// Lines and columns are 1-based and columns count characters, not bytes.
+// EndLine and EndColumn point just past the last character of the token,
+// they only differ from Line for multi-line strings.
 type Token struct {
 	Type    TokenType
 	Lexeme  string
 	Literal any
-	Line    int
+
+	Line, Column       int
+	EndLine, EndColumn int
+	Offset             int // byte offset of the first character in the source
+
+	// what the token was scanned from, so errors can quote the right code
+	// even after later sources have been run
+	Source *Source
+
+	// leading trivia, kept for the Formatter: comments between the previous
+	// token and this one, and whether a blank line directly precedes it
+	Comments  []Comment
+	BlankLine bool
 }
 
-func NewToken(tokenType TokenType, lexeme string, literal any, line int) Token {
+// Comment is a `//` comment, Text includes the slashes. Trailing comments
+// start on the same line as the token before them.
+type Comment struct {
+	Text      string
+	Trailing  bool
+	BlankLine bool
+}
+
+func NewToken(tokenType TokenType, lexeme string, literal any, span Span) Token {
 	return Token{
-		Type:    tokenType,
-		Lexeme:  lexeme,
-		Literal: literal,
-		Line:    line,
+		Type:      tokenType,
+		Lexeme:    lexeme,
+		Literal:   literal,
+		Line:      span.Line,
+		Column:    span.Column,
+		EndLine:   span.EndLine,
+		EndColumn: span.EndColumn,
+		Offset:    span.Offset,
+		Source:    span.Source,
 	}
 }
 
@@ -20,6 +50,35 @@ func (t Token) String() string {
 	return t.Lexeme
 }
 
+func (t Token) Span() Span {
+	return Span{
+		Line:      t.Line,
+		Column:    t.Column,
+		EndLine:   t.EndLine,
+		EndColumn: t.EndColumn,
+		Offset:    t.Offset,
+		Length:    len(t.Lexeme),
+		Source:    t.Source,
+	}
+}
+
+// Span is a region of source text, see Token for how positions are counted
+type Span struct {
+	Line, Column       int
+	EndLine, EndColumn int
+	Offset, Length     int
+	Source             *Source
+}
+
+// Source is a named piece of Lox code, eg. a script file or one REPL entry
+type Source struct {
+	Name, Text string
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("%d:%d", s.Line, s.Column)
+}
+
 type TokenType int
 
 const (
@@ -51,7 +110,9 @@ const (
 	NUMBER
 
 	AND
+	BREAK
 	CLASS
+	CONTINUE
 	ELSE
 	FALSE
 	FUN
@@ -125,8 +186,12 @@ func (t TokenType) String() string {
 
 	case AND:
 		return "AND"
+	case BREAK:
+		return "BREAK"
 	case CLASS:
 		return "CLASS"
+	case CONTINUE:
+		return "CONTINUE"
 	case ELSE:
 		return "ELSE"
 	case FALSE:
@@ -0,0 +1,139 @@
+package lox
+
+import "fmt"
+
+// Kind is the type of a value as Lox programs see it, eg. through the type
+// native
+type Kind int
+
+const (
+	NilKind Kind = iota
+	BoolKind
+	NumberKind
+	StringKind
+	FunctionKind
+	ClassKind
+	InstanceKind
+	ListKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case NilKind:
+		return "nil"
+	case BoolKind:
+		return "boolean"
+	case NumberKind:
+		return "number"
+	case StringKind:
+		return "string"
+	case FunctionKind:
+		return "function"
+	case ClassKind:
+		return "class"
+	case InstanceKind:
+		return "instance"
+	case ListKind:
+		return "list"
+	}
+	return "unknown"
+}
+
+// Value is anything an expression can evaluate to. A new type of value only
+// needs these methods, the interpreter leaves printing, truthiness and
+// equality to them.
+type Value interface {
+	Kind() Kind
+	// String is how print and string concatenation show the value
+	String() string
+	Equals(other Value) bool
+	Truthy() bool
+}
+
+type Nil struct{}
+
+func (Nil) Kind() Kind              { return NilKind }
+func (Nil) String() string          { return "nil" }
+func (Nil) Equals(other Value) bool { return other.Kind() == NilKind }
+func (Nil) Truthy() bool            { return false }
+
+type Bool bool
+
+func (b Bool) Kind() Kind     { return BoolKind }
+func (b Bool) String() string { return fmt.Sprintf("%v", bool(b)) }
+func (b Bool) Truthy() bool   { return bool(b) }
+
+func (b Bool) Equals(other Value) bool {
+	o, ok := other.(Bool)
+	return ok && b == o
+}
+
+type Number float64
+
+func (n Number) Kind() Kind     { return NumberKind }
+func (n Number) String() string { return fmt.Sprintf("%v", float64(n)) }
+func (n Number) Truthy() bool   { return n != 0 }
+
+// NaN is not equal to itself, as in IEEE 754
+func (n Number) Equals(other Value) bool {
+	o, ok := other.(Number)
+	return ok && n == o
+}
+
+type String string
+
+func (s String) Kind() Kind     { return StringKind }
+func (s String) String() string { return string(s) }
+func (s String) Truthy() bool   { return s != "" }
+
+func (s String) Equals(other Value) bool {
+	o, ok := other.(String)
+	return ok && s == o
+}
+
+// Inspect shows a value the way a REPL echoes it, like String but with
+// strings quoted so "nil" and nil can be told apart
+func Inspect(value Value) string {
+	if s, ok := value.(String); ok {
+		return `"` + string(s) + `"`
+	}
+	return value.String()
+}
+
+// valueOf converts a Go value, the literal of a token or the result of an
+// embedder's native, to a Value. Go's nil is Lox's nil and Values are passed
+// through. It reports false for Go types with no Lox equivalent.
+func valueOf(v any) (Value, bool) {
+	switch v := v.(type) {
+	case nil:
+		return Nil{}, true
+	case Value:
+		return v, true
+	case bool:
+		return Bool(v), true
+	case float64:
+		return Number(v), true
+	case int:
+		return Number(v), true
+	case string:
+		return String(v), true
+	}
+	return nil, false
+}
+
+// goValue is the reverse of valueOf for embedders: nil, bool, float64 and
+// string for the basic types, functions, classes, instances and lists as
+// they are
+func goValue(v Value) any {
+	switch v := v.(type) {
+	case Nil:
+		return nil
+	case Bool:
+		return bool(v)
+	case Number:
+		return float64(v)
+	case String:
+		return string(v)
+	}
+	return v
+}
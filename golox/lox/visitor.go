@@ -0,0 +1,31 @@
+package lox
+
+type Visitor interface {
+	VisitAssignmentExpr(expr *AssignmentExpr) (any, error)
+	VisitConditionalExpr(expr *ConditionalExpr) (any, error)
+	VisitLogicalExpr(expr *LogicalExpr) (any, error)
+	VisitBinaryExpr(expr *BinaryExpr) (any, error)
+	VisitGroupingExpr(expr *GroupingExpr) (any, error)
+	VisitLiteralExpr(expr *LiteralExpr) (any, error)
+	VisitUnaryExpr(expr *UnaryExpr) (any, error)
+	VisitCallExpr(expr *CallExpr) (any, error)
+	VisitGetExpr(expr *GetExpr) (any, error)
+	VisitSetExpr(expr *SetExpr) (any, error)
+	VisitSuperExpr(expr *SuperExpr) (any, error)
+	VisitThisExpr(expr *ThisExpr) (any, error)
+	VisitVariableExpr(expr *VariableExpr) (any, error)
+}
+
+type StmtVisitor interface {
+	VisitClassStmt(stmt ClassStmt) error
+	VisitFunctionStmt(stmt FunctionStmt) error
+	VisitVariableStmt(stmt VariableStmt) error
+	VisitExpressionStmt(stmt ExpressionStmt) error
+	VisitPrintStmt(stmt PrintStmt) error
+	VisitIfStmt(stmt IfStmt) error
+	VisitWhileStmt(stmt WhileStmt) error
+	VisitBlockStmt(stmt BlockStmt) error
+	VisitReturnStmt(stmt ReturnStmt) error
+	VisitBreakStmt(stmt BreakStmt) error
+	VisitContinueStmt(stmt ContinueStmt) error
+}
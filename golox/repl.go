@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alexleyoung/golox/editor"
+	"github.com/alexleyoung/golox/lox"
+)
+
+func runPrompt(runtime *lox.Runtime, stdin *bufio.Reader) {
+	readLine := newLineReader(runtime, stdin)
+	for true {
+		fmt.Print("\n")
+		source, err := readStatement(readLine, runtime)
+		if errors.Is(err, editor.ErrInterrupted) {
+			continue
+		}
+		if err != nil {
+			if err != io.EOF {
+				fmt.Println(err)
+			}
+			break
+		}
+
+		if isMetaCommand(source) {
+			runMetaCommand(runtime, source)
+			continue
+		}
+
+		// expression values are echoed and kept in `_`
+		value, ok, err := runtime.EvalLine(source)
+		if err == nil && ok {
+			runtime.Define("_", value)
+			fmt.Print(lox.Inspect(value))
+		}
+	}
+}
+
+// newLineReader returns a function that shows a prompt and reads a line.
+// On a terminal it uses the line editor with history in ~/.golox_history
+// and completion of keywords and globals, otherwise it reads plain lines.
+func newLineReader(runtime *lox.Runtime, stdin *bufio.Reader) func(prompt string) (string, error) {
+	historyFile := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyFile = filepath.Join(home, ".golox_history")
+	}
+	lineEditor, err := editor.New(os.Stdin, os.Stdout, historyFile)
+	if err == nil {
+		lineEditor.SetReader(stdin)
+		lineEditor.SetCompleter(runtime.Completions)
+		return lineEditor.ReadLine
+	}
+
+	return func(prompt string) (string, error) {
+		fmt.Print(prompt)
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+}
+
+// readStatement reads lines until they form a complete statement, showing a
+// continuation prompt in between. An empty continuation line gives up and
+// evaluates what was typed so the error gets reported.
+func readStatement(readLine func(prompt string) (string, error), runtime *lox.Runtime) (string, error) {
+	source, err := readLine("> ")
+	if err != nil {
+		return "", err
+	}
+	for !isMetaCommand(source) && runtime.Incomplete(source) {
+		line, err := readLine("... ")
+		if err == io.EOF {
+			return source, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		source += "\n" + line
+	}
+	return source, nil
+}
+
+type metaCommand struct {
+	name, args, help string
+	run              func(runtime *lox.Runtime, arg string) error
+}
+
+// metaCommands are the colon-prefixed commands the REPL accepts besides
+// Lox, eg. `:type 1 + 2`
+var metaCommands []metaCommand
+
+func init() {
+	// set in init since :help refers back to the list
+	metaCommands = []metaCommand{
+		{"env", "", "list the variables in scope", showEnv},
+		{"type", "expr", "evaluate expr and show its type", showType},
+		{"ast", "code", "show the syntax tree of code", showAst},
+		{"tokens", "code", "show the tokens of code", showTokens},
+		{"load", "file", "run a file in this session", loadFile},
+		{"reset", "", "forget every definition", resetSession},
+		{"time", "expr", "evaluate expr and show how long it took", timeExpression},
+		{"help", "", "show this list", showHelp},
+	}
+}
+
+func isMetaCommand(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), ":")
+}
+
+// runMetaCommand runs a line such as `:env`. Lox errors are reported by the
+// runtime as usual, other problems like an unknown command go to stderr.
+func runMetaCommand(runtime *lox.Runtime, line string) error {
+	name, arg, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), ":"), " ")
+	arg = strings.TrimSpace(arg)
+	for _, command := range metaCommands {
+		if command.name != name {
+			continue
+		}
+		if command.args != "" && arg == "" {
+			return commandError(fmt.Errorf("usage: :%s %s", command.name, command.args))
+		}
+		return command.run(runtime, arg)
+	}
+	return commandError(fmt.Errorf("unknown command :%s, try :help", name))
+}
+
+func commandError(err error) error {
+	fmt.Fprintln(os.Stderr, err)
+	return err
+}
+
+// showEnv lists the globals, the only scope there is at the prompt
+func showEnv(runtime *lox.Runtime, _ string) error {
+	globals := runtime.Globals()
+	names := make([]string, 0, len(globals))
+	for name := range globals {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "globals:")
+	for _, name := range names {
+		value := globals[name]
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", name, value.Kind(), lox.Inspect(value))
+	}
+	return w.Flush()
+}
+
+func showType(runtime *lox.Runtime, arg string) error {
+	value, err := evalExpression(runtime, arg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(value.Kind())
+	return nil
+}
+
+func showAst(runtime *lox.Runtime, arg string) error {
+	statements, err := runtime.ParseLine(arg)
+	if err != nil {
+		return err
+	}
+	s, err := (&lox.AstPrinter{}).PrintProgram(statements)
+	if err != nil {
+		return err
+	}
+	fmt.Print(s)
+	return nil
+}
+
+func showTokens(runtime *lox.Runtime, arg string) error {
+	tokens, err := runtime.Tokens("<repl>", arg)
+	if err != nil {
+		return err
+	}
+	printTokens(tokens)
+	return nil
+}
+
+func loadFile(runtime *lox.Runtime, arg string) error {
+	source, err := os.ReadFile(arg)
+	if err != nil {
+		return commandError(err)
+	}
+	_, err = runtime.EvalFile(arg, string(source))
+	return err
+}
+
+func resetSession(runtime *lox.Runtime, _ string) error {
+	runtime.Reset()
+	return nil
+}
+
+func timeExpression(runtime *lox.Runtime, arg string) error {
+	start := time.Now()
+	value, err := evalExpression(runtime, arg)
+	elapsed := time.Since(start)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s (%s)\n", lox.Inspect(value), elapsed)
+	return nil
+}
+
+func showHelp(*lox.Runtime, string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, command := range metaCommands {
+		fmt.Fprintf(w, ":%s %s\t%s\n", command.name, command.args, command.help)
+	}
+	return w.Flush()
+}
+
+// evalExpression evaluates source, which must be a single expression, in
+// the session without storing the result in `_`
+func evalExpression(runtime *lox.Runtime, source string) (lox.Value, error) {
+	statements, err := runtime.ParseLine(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) != 1 {
+		return nil, commandError(errors.New("expected a single expression"))
+	}
+	if _, ok := statements[0].(lox.ExpressionStmt); !ok {
+		return nil, commandError(errors.New("expected a single expression"))
+	}
+	value, _, err := runtime.EvalLine(source)
+	return value, err
+}